@@ -1,30 +1,68 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/kjunh972/loex/internal/config"
 	"github.com/kjunh972/loex/internal/detector"
+	"github.com/kjunh972/loex/internal/env"
+	"github.com/kjunh972/loex/internal/health"
 	"github.com/kjunh972/loex/internal/logger"
 	"github.com/kjunh972/loex/internal/process"
 	"github.com/kjunh972/loex/pkg/models"
 )
 
 var (
-	dirFlag string
+	dirFlag            string
+	kindFlag           string
+	dependsOnFlag      []string
+	portsFlag          []string
+	namedPortFlag      []string
+	recursiveFlag      bool
+	depthFlag          int
+	ignoreFlag         []string
+	healthFlag         string
+	healthStatusFlag   int
+	healthTimeoutFlag  time.Duration
+	healthIntervalFlag time.Duration
+	healthRetriesFlag  int
+	restartFlag        string
+	maxRestartsFlag    int
+	restartWindowFlag  time.Duration
+	logFormatFlag      string
+	shellFlag          string
+	stopSignalFlag     string
+	stopTimeoutFlag    time.Duration
 )
 
 var configCmd = &cobra.Command{
 	Use:   "config [project] [service] [command]",
 	Short: "Configure project services", 
-	Long:  `Configure services for projects using auto-detection or interactive wizard.`,
-	Args:  cobra.RangeArgs(0, 3),
+	Long:  `Configure services for projects using auto-detection or interactive wizard.
+
+The command is split like a shell would split it, so quoted arguments work.
+Commands using pipes, &&, redirection or variables are run with /bin/sh -c;
+use --shell to always run the command with a shell. To give the exact
+arguments instead, pass them after --:
+
+  loex config myapp api -- node server.js --name "my api"`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			if dash != 2 || len(args) < 3 {
+				return fmt.Errorf("use: loex config [project] [service] -- [arg...]")
+			}
+			return nil
+		}
+		return cobra.RangeArgs(0, 3)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
 			cmd.Help()
@@ -44,12 +82,20 @@ var configCmd = &cobra.Command{
 		}
 		
 		projectName := args[0]
-		serviceTypeStr := args[1]
+		serviceName := args[1]
 		command := args[2]
+
+		var argv []string
+		if cmd.ArgsLenAtDash() == 2 {
+			argv = args[2:]
+			command = process.QuoteCommand(argv)
+		} else if _, _, err := process.SplitCommand(command); err != nil {
+			fmt.Printf("Invalid command: %v\n", err)
+			os.Exit(1)
+		}
 		
-		serviceType := models.ServiceType(serviceTypeStr)
-		if serviceType != models.ServiceFrontend && serviceType != models.ServiceBackend && serviceType != models.ServiceDB {
-			fmt.Printf("Invalid service type '%s'. Use: frontend, backend, db\n", serviceTypeStr)
+		if err := validateServiceName(serviceName); err != nil {
+			fmt.Printf("Invalid service name: %v\n", err)
 			fmt.Printf("Configure Services:\n")
 			fmt.Printf("  loex config detect %s    # Auto-detect (recommended)\n", projectName)
 			fmt.Printf("  loex config wizard %s    # Interactive setup\n", projectName)
@@ -73,7 +119,7 @@ var configCmd = &cobra.Command{
 		} else {
 			project = &models.Project{
 				Name:     projectName,
-				Services: make(map[models.ServiceType]models.Service),
+				Services: make(map[string]models.Service),
 				Created:  time.Now(),
 				Updated:  time.Now(),
 			}
@@ -101,38 +147,141 @@ var configCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		fmt.Printf("\nConfiguration Summary:\n")
-		fmt.Printf("  Project: %s\n", projectName)
-		fmt.Printf("  Service: %s\n", serviceType)
-		fmt.Printf("  Command: %s\n", command)
-		fmt.Printf("  Directory: %s\n", serviceDir)
-		fmt.Print("\nSave this configuration? (Y/n): ")
+		// Every flag is validated before the summary, so the confirmation
+		// only gates the write.
+		service := project.Services[serviceName]
+		service.Command = command
+		service.Args = argv
+		service.Dir = serviceDir
+		if cmd.Flags().Changed("shell") {
+			if shellFlag == "none" {
+				shellFlag = ""
+			}
+			service.Shell = shellFlag
+		}
+		if kindFlag != "" {
+			service.Kind = models.ServiceType(kindFlag)
+		}
+		if cmd.Flags().Changed("depends-on") {
+			service.DependsOn = dependsOnFlag
+		}
+		if cmd.Flags().Changed("ports") {
+			ports, err := parsePorts(portsFlag)
+			if err != nil {
+				fmt.Printf("Invalid ports: %v\n", err)
+				os.Exit(1)
+			}
+			service.Ports = ports
+		}
+		if cmd.Flags().Changed("named-port") {
+			if err := applyNamedPorts(&service, namedPortFlag); err != nil {
+				fmt.Printf("Invalid named port: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if cmd.Flags().Changed("health") {
+			if healthFlag == "" || healthFlag == "none" {
+				service.Healthcheck = nil
+			} else {
+				healthcheck, err := health.ParseSpec(healthFlag)
+				if err != nil {
+					fmt.Printf("Invalid health check: %v\n", err)
+					os.Exit(1)
+				}
+				healthcheck.HTTPStatus = healthStatusFlag
+				healthcheck.Timeout = models.Duration(healthTimeoutFlag)
+				healthcheck.Interval = models.Duration(healthIntervalFlag)
+				healthcheck.Retries = healthRetriesFlag
+				service.Healthcheck = healthcheck
+			}
+		}
+		if cmd.Flags().Changed("restart") {
+			switch restartFlag {
+			case models.RestartNever, "":
+				service.Restart = nil
+			case models.RestartOnFailure, models.RestartAlways:
+				service.Restart = &models.RestartPolicy{
+					Policy:      restartFlag,
+					MaxRestarts: maxRestartsFlag,
+					Window:      models.Duration(restartWindowFlag),
+				}
+			default:
+				fmt.Printf("Invalid restart policy '%s'. Use: never, on-failure, always\n", restartFlag)
+				os.Exit(1)
+			}
+		}
+		if cmd.Flags().Changed("log-format") {
+			switch logFormatFlag {
+			case models.LogFormatRaw, "":
+				service.LogFormat = ""
+			case models.LogFormatTimestamped, models.LogFormatJSON:
+				service.LogFormat = logFormatFlag
+			default:
+				fmt.Printf("Invalid log format '%s'. Use: raw, timestamped, json\n", logFormatFlag)
+				os.Exit(1)
+			}
+		}
+		if cmd.Flags().Changed("stop-signal") {
+			if _, err := process.ParseSignal(stopSignalFlag); err != nil {
+				fmt.Printf("Invalid stop signal: %v\n", err)
+				os.Exit(1)
+			}
+			service.StopSignal = stopSignalFlag
+		}
+		if cmd.Flags().Changed("stop-timeout") {
+			service.StopTimeout = models.Duration(stopTimeoutFlag)
+		}
+		project.Services[serviceName] = service
 
-		reader := bufio.NewReader(os.Stdin)
-		response, err := reader.ReadString('\n')
-		if err != nil {
-			fmt.Printf("Error reading input: %v\n", err)
+		if err := project.ValidateDependencies(); err != nil {
+			fmt.Printf("Invalid dependencies: %v\n", err)
 			os.Exit(1)
 		}
 
-		response = strings.TrimSpace(strings.ToLower(response))
-		if response != "" && response != "y" && response != "yes" {
+		fmt.Printf("\nConfiguration Summary:\n")
+		fmt.Printf("  Project: %s\n", projectName)
+		fmt.Printf("  Service: %s\n", serviceName)
+		if kindFlag != "" {
+			fmt.Printf("  Kind: %s\n", kindFlag)
+		}
+		if len(dependsOnFlag) > 0 {
+			fmt.Printf("  Depends on: %s\n", strings.Join(dependsOnFlag, ", "))
+		}
+		if len(portsFlag) > 0 {
+			fmt.Printf("  Ports: %s\n", strings.Join(portsFlag, ", "))
+		}
+		if len(namedPortFlag) > 0 {
+			fmt.Printf("  Named ports: %s\n", strings.Join(namedPortFlag, ", "))
+		}
+		if healthFlag != "" {
+			fmt.Printf("  Health check: %s\n", healthFlag)
+		}
+		if restartFlag != "" {
+			fmt.Printf("  Restart policy: %s\n", restartFlag)
+		}
+		if logFormatFlag != "" {
+			fmt.Printf("  Log format: %s\n", logFormatFlag)
+		}
+		if stopSignalFlag != "" {
+			fmt.Printf("  Stop signal: %s\n", stopSignalFlag)
+		}
+		fmt.Printf("  Command: %s\n", command)
+		fmt.Printf("  Directory: %s\n", serviceDir)
+		if !confirm("\nSave this configuration? (Y/n): ", true) {
 			fmt.Printf("Configuration cancelled\n")
 			os.Exit(0)
 		}
 
-		project.Services[serviceType] = models.Service{
-			Type:    serviceType,
-			Command: command,
-			Dir:     serviceDir,
-		}
-
-		if err := configManager.SaveProject(project); err != nil {
+		err = configManager.UpdateProject(projectName, func(current *models.Project) error {
+			current.Services[serviceName] = service
+			return current.ValidateDependencies()
+		})
+		if err != nil {
 			fmt.Printf("Failed to save project: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("Service '%s' configured for project '%s'\n", serviceType, projectName)
+		fmt.Printf("Service '%s' configured for project '%s'\n", serviceName, projectName)
 	},
 }
 
@@ -140,7 +289,11 @@ var configCmd = &cobra.Command{
 var configWizardCmd = &cobra.Command{
 	Use:   "wizard [project]",
 	Short: "Interactive project configuration",
-	Long:  `Interactive wizard to configure all services for a project.`,
+	Long:  `Interactive wizard to configure all services for a project.
+
+To run without prompts, name the services with --service; --dir and --command
+answer the corresponding questions and --yes accepts the defaults and detected
+commands for the rest.`,
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		projectName := args[0]
@@ -162,25 +315,56 @@ var configWizardCmd = &cobra.Command{
 		} else {
 			project = &models.Project{
 				Name:     projectName,
-				Services: make(map[models.ServiceType]models.Service),
+				Services: make(map[string]models.Service),
 				Created:  time.Now(),
 				Updated:  time.Now(),
 			}
 			fmt.Printf("Creating new project '%s'\n\n", projectName)
 		}
 
-		detector := detector.New()
-		reader := bufio.NewReader(os.Stdin)
+		if commandFlag != "" && len(configServiceFlag) != 1 {
+			fmt.Printf("--command requires exactly one --service\n")
+			os.Exit(1)
+		}
+
+		hint := "Use --service, --command, --dir and --yes to configure without prompts."
+		var configured []string
 
-		services := []models.ServiceType{models.ServiceFrontend, models.ServiceBackend, models.ServiceDB}
+		if len(configServiceFlag) == 0 {
+			fmt.Printf("Add services one at a time (e.g. api, worker, redis).\n\n")
+		}
+
+		for i := 0; ; i++ {
+			var serviceName string
+			if len(configServiceFlag) > 0 {
+				if i == len(configServiceFlag) {
+					break
+				}
+				serviceName = configServiceFlag[i]
+				if err := validateServiceName(serviceName); err != nil {
+					fmt.Printf("Invalid service name: %v\n", err)
+					os.Exit(1)
+				}
+			} else {
+				serviceName = prompt("Enter service name (press Enter to finish): ", hint)
+				if serviceName == "" {
+					break
+				}
+				if err := validateServiceName(serviceName); err != nil {
+					fmt.Printf(" %v\n\n", err)
+					continue
+				}
+			}
 
-		for _, serviceType := range services {
-			fmt.Printf("Configuring %s service:\n", serviceType)
+			fmt.Printf("Configuring %s service:\n", serviceName)
 
 			// Get directory
-			fmt.Print("Enter directory path (press Enter for current directory): ")
-			dirInput, _ := reader.ReadString('\n')
-			dirInput = strings.TrimSpace(dirInput)
+			var dirInput string
+			if cmd.Flags().Changed("dir") {
+				dirInput = dirFlag
+			} else if !yesFlag {
+				dirInput = prompt("Enter directory path (press Enter for current directory): ", hint)
+			}
 			
 			var serviceDir string
 			if dirInput == "" {
@@ -200,48 +384,80 @@ var configWizardCmd = &cobra.Command{
 			fmt.Printf("Using directory: %s\n", serviceDir)
 
 			if _, err := os.Stat(serviceDir); os.IsNotExist(err) {
-				fmt.Printf(" Directory does not exist, skipping %s service\n\n", serviceType)
+				fmt.Printf(" Directory does not exist, skipping %s service\n\n", serviceName)
 				continue
 			}
 
-			results, err := detector.DetectServices(serviceDir)
-			var command string
+			command := commandFlag
+			kind := models.ServiceType(kindFlag)
+			var envFiles []string
+			var ports []int
+			var env map[string]string
 
-			if err == nil {
-				for _, result := range results {
-					if result.Service == serviceType {
-						fmt.Printf("Auto-detected: %s\n", result.Command)
-						fmt.Printf("   Reason: %s\n", result.DetectionReason)
-						fmt.Print("Use this command? (Y/n): ")
-
-						response, _ := reader.ReadString('\n')
-						response = strings.TrimSpace(strings.ToLower(response))
-						if response == "" || response == "y" || response == "yes" {
-							command = result.Command
-							break
+			detected := false
+			if command == "" {
+				serviceDetector := newDetector(serviceDir)
+				results, err := serviceDetector.DetectServices(serviceDir)
+				if err != nil {
+					fmt.Printf("Auto-detection failed: %v\n", err)
+				}
+				if dbHint := serviceDetector.DatabaseHint(serviceDir, results); dbHint != "" {
+					fmt.Printf("\n%s\n", dbHint)
+				}
+				if len(results) > 0 {
+					// Candidates of every kind are ranked together since
+					// the service is named by the user.
+					var candidates []detector.DetectionResult
+					for _, result := range results {
+						candidates = append(candidates, result)
+						candidates = append(candidates, result.Alternatives...)
+					}
+					slices.SortStableFunc(candidates, func(a, b detector.DetectionResult) int {
+						return b.Confidence - a.Confidence
+					})
+
+					choice, custom := chooseCandidate(candidates, false, hint)
+					if choice != nil {
+						detected = true
+						command = choice.Command
+						if kind == "" {
+							kind = choice.Kind
 						}
+						envFiles = choice.EnvFiles
+						ports = choice.Ports
+						env = choice.Env
+					} else {
+						command = custom
 					}
 				}
 			}
 
-			if command == "" {
-				fmt.Printf("Enter command for %s service: ", serviceType)
-				cmdInput, _ := reader.ReadString('\n')
-				command = strings.TrimSpace(cmdInput)
-			}
+			if !detected {
+				if command == "" {
+					command = prompt(fmt.Sprintf("Enter command for %s service: ", serviceName), hint)
+				}
 
-			if command == "" {
-				fmt.Printf(" No command provided, skipping %s service\n\n", serviceType)
-				continue
+				if command == "" {
+					fmt.Printf(" No command provided, skipping %s service\n\n", serviceName)
+					continue
+				}
+
+				if kind == "" && !yesFlag {
+					kind = models.ServiceType(prompt(fmt.Sprintf("Enter kind for %s service (optional, e.g. frontend, backend, db): ", serviceName), hint))
+				}
 			}
 
-			project.Services[serviceType] = models.Service{
-				Type:    serviceType,
-				Command: command,
-				Dir:     serviceDir,
+			project.Services[serviceName] = models.Service{
+				Kind:     kind,
+				Command:  command,
+				Dir:      serviceDir,
+				Ports:    ports,
+				Env:      env,
+				EnvFiles: envFiles,
 			}
+			configured = append(configured, serviceName)
 
-			fmt.Printf("%s service configured\n\n", serviceType)
+			fmt.Printf("%s service configured\n\n", serviceName)
 		}
 
 		if len(project.Services) == 0 {
@@ -249,7 +465,14 @@ var configWizardCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		if err := configManager.SaveProject(project); err != nil {
+		err = configManager.UpdateProject(projectName, func(current *models.Project) error {
+			for _, serviceName := range configured {
+				current.Services[serviceName] = project.Services[serviceName]
+			}
+			project = current
+			return nil
+		})
+		if err != nil {
 			fmt.Printf("Failed to save project: %v\n", err)
 			os.Exit(1)
 		}
@@ -262,7 +485,20 @@ var configWizardCmd = &cobra.Command{
 var configDetectCmd = &cobra.Command{
 	Use:   "detect [project]",
 	Short: "Auto-detect and configure services in current directory",
-	Long:  `Automatically detect services in the current directory and configure them for the project.`,
+	Long:  `Automatically detect services in the current directory and configure them for the project.
+
+Use --accept-all (or --yes) to accept every detected command without prompting,
+and --service to configure only some of the detected services.
+
+With --recursive, subdirectories are scanned as well, for repositories holding
+several apps. Members of npm, yarn and pnpm workspaces, go.work, Cargo
+workspaces and Gradle multi-projects are always found; other directories are
+scanned up to --depth levels. Each app becomes a service named after its
+directory that runs in it.
+
+Services are proposed by detection rules; see 'loex detect' to list them, add
+your own and find out why a directory was detected the way it was. When several
+rules match, their commands are listed best first to choose from.`,
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		projectName := args[0]
@@ -284,7 +520,7 @@ var configDetectCmd = &cobra.Command{
 		} else {
 			project = &models.Project{
 				Name:     projectName,
-				Services: make(map[models.ServiceType]models.Service),
+				Services: make(map[string]models.Service),
 				Created:  time.Now(),
 				Updated:  time.Now(),
 			}
@@ -292,27 +528,62 @@ var configDetectCmd = &cobra.Command{
 		}
 
 		cwd, _ := os.Getwd()
+		if dirFlag != "" {
+			if cwd, err = filepath.Abs(dirFlag); err != nil {
+				fmt.Printf("Invalid directory path: %v\n", err)
+				os.Exit(1)
+			}
+		}
 		fmt.Printf("Analyzing current directory: %s\n\n", cwd)
 
-		detector := detector.New()
-		results, err := detector.DetectServices(cwd)
+		serviceDetector := newDetector(cwd)
+		var results []detector.DetectionResult
+		if recursiveFlag {
+			results, err = serviceDetector.DetectRecursive(cwd, detector.ScanOptions{
+				MaxDepth: depthFlag,
+				Ignore:   append(slices.Clone(detector.DefaultIgnore), ignoreFlag...),
+			})
+		} else {
+			results, err = serviceDetector.DetectServices(cwd)
+		}
 		if err != nil {
 			fmt.Printf("Failed to detect services: %v\n", err)
 			os.Exit(1)
 		}
+		if dbHint := serviceDetector.DatabaseHint(cwd, results); dbHint != "" {
+			fmt.Printf("%s\n\n", dbHint)
+		}
 
 		if len(results) == 0 {
 			fmt.Printf("No services detected in current directory.\n")
-			fmt.Printf("Make sure you're in a project directory (with package.json, go.mod, etc.)\n")
+			fmt.Printf("Make sure you're in a project directory (with a Procfile, package.json, go.mod, etc.)\n")
 			return
 		}
 
-		var existingServices []models.ServiceType
+		if len(configServiceFlag) > 0 {
+			detected := results
+			results = nil
+			for _, serviceName := range configServiceFlag {
+				found := false
+				for _, result := range detected {
+					if result.Name == serviceName {
+						results = append(results, result)
+						found = true
+					}
+				}
+				if !found {
+					fmt.Printf("Service '%s' was not detected\n", serviceName)
+					os.Exit(1)
+				}
+			}
+		}
+
+		var existingServices []string
 		var hasNewServices bool
 		
 		for _, result := range results {
-			if _, exists := project.Services[result.Service]; exists {
-				existingServices = append(existingServices, result.Service)
+			if _, exists := project.Services[result.Name]; exists {
+				existingServices = append(existingServices, result.Name)
 			} else {
 				hasNewServices = true
 			}
@@ -320,9 +591,9 @@ var configDetectCmd = &cobra.Command{
 
 		if len(existingServices) > 0 {
 			fmt.Printf("Already configured services in this project:\n")
-			for _, serviceType := range existingServices {
-				service := project.Services[serviceType]
-				fmt.Printf("  - %s: %s\n", serviceType, service.Command)
+			for _, serviceName := range existingServices {
+				service := project.Services[serviceName]
+				fmt.Printf("  - %s: %s\n", serviceName, service.Command)
 			}
 			fmt.Println()
 		}
@@ -334,50 +605,69 @@ var configDetectCmd = &cobra.Command{
 
 		fmt.Printf("New services detected:\n")
 		for _, result := range results {
-			if _, exists := project.Services[result.Service]; !exists {
-				fmt.Printf("  - %s: %s (%s)\n", result.Service, result.Command, result.DetectionReason)
+			if _, exists := project.Services[result.Name]; !exists {
+				if result.Dir != "" && result.Dir != cwd {
+					fmt.Printf("  - %s: %s in %s (%s)\n", result.Name, result.Command, relativePath(cwd, result.Dir), result.DetectionReason)
+				} else {
+					fmt.Printf("  - %s: %s (%s)\n", result.Name, result.Command, result.DetectionReason)
+				}
+				if len(result.Alternatives) > 0 {
+					fmt.Printf("    %d other candidate(s)\n", len(result.Alternatives))
+				}
 			}
 		}
 		fmt.Println()
 
-		reader := bufio.NewReader(os.Stdin)
-		
+		var configured []string
 		for _, result := range results {
-			if _, exists := project.Services[result.Service]; exists {
+			if _, exists := project.Services[result.Name]; exists {
 				continue
 			}
-			fmt.Printf("Configuring %s service:\n", result.Service)
-			fmt.Printf("Auto-detected command: %s\n", result.Command)
-			fmt.Printf("Reason: %s\n", result.DetectionReason)
-			fmt.Print("Use this command? (Y/n): ")
+			fmt.Printf("Configuring %s service:\n", result.Name)
+			if result.Dir != "" && result.Dir != cwd {
+				fmt.Printf("Directory: %s\n", relativePath(cwd, result.Dir))
+			}
 
-			response, _ := reader.ReadString('\n')
-			response = strings.TrimSpace(strings.ToLower(response))
-			
-			var command string
-			if response == "" || response == "y" || response == "yes" {
-				command = result.Command
-			} else {
-				fmt.Printf("Enter custom command for %s service: ", result.Service)
-				cmdInput, _ := reader.ReadString('\n')
-				command = strings.TrimSpace(cmdInput)
+			candidates := append([]detector.DetectionResult{result}, result.Alternatives...)
+			chosen := result
+			choice, command := chooseCandidate(candidates, acceptAllFlag, "Use --accept-all to use the detected commands.")
+			if choice != nil {
+				chosen = *choice
+				command = choice.Command
+			} else if command == "" {
+				command = prompt(fmt.Sprintf("Enter custom command for %s service: ", result.Name), "Use --accept-all to use the detected commands.")
 				
 				if command == "" {
-					fmt.Printf("No command provided, skipping %s service\n\n", result.Service)
+					fmt.Printf("No command provided, skipping %s service\n\n", result.Name)
 					continue
 				}
 			}
 
-			project.Services[result.Service] = models.Service{
-				Type:    result.Service,
-				Command: command,
-				Dir:     cwd,
+			serviceDir := cwd
+			if result.Dir != "" {
+				serviceDir = result.Dir
 			}
+			project.Services[result.Name] = models.Service{
+				Kind:     result.Kind,
+				Command:  command,
+				Dir:      serviceDir,
+				Ports:    chosen.Ports,
+				Env:      chosen.Env,
+				EnvFiles: chosen.EnvFiles,
+			}
+			configured = append(configured, result.Name)
 
-			fmt.Printf("%s service configured\n\n", result.Service)
+			fmt.Printf("%s service configured\n\n", result.Name)
 		}
 
-		if err := configManager.SaveProject(project); err != nil {
+		err = configManager.UpdateProject(projectName, func(current *models.Project) error {
+			for _, serviceName := range configured {
+				current.Services[serviceName] = project.Services[serviceName]
+			}
+			project = current
+			return nil
+		})
+		if err != nil {
 			fmt.Printf("Failed to save project: %v\n", err)
 			os.Exit(1)
 		}
@@ -390,17 +680,14 @@ var configDetectCmd = &cobra.Command{
 var configEditCmd = &cobra.Command{
 	Use:   "edit [project] [service]",
 	Short: "Edit service configuration",
-	Long:  `Edit the command and directory for an existing service.`,
+	Long:  `Edit the command and directory for an existing service.
+
+--command and --dir set the new values without prompting; with --yes, values
+not given are kept and the changes are saved without confirmation.`,
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		projectName := args[0]
-		serviceTypeStr := args[1]
-
-		serviceType := models.ServiceType(serviceTypeStr)
-		if serviceType != models.ServiceFrontend && serviceType != models.ServiceBackend && serviceType != models.ServiceDB {
-			fmt.Printf("Invalid service type '%s'. Use: frontend, backend, db\n", serviceTypeStr)
-			os.Exit(1)
-		}
+		serviceName := args[1]
 
 		configManager, err := config.NewManager()
 		if err != nil {
@@ -419,37 +706,40 @@ var configEditCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		service, exists := project.Services[serviceType]
+		service, exists := project.Services[serviceName]
 		if !exists {
-			fmt.Printf("Service '%s' not configured for project '%s'\n", serviceType, projectName)
+			fmt.Printf("Service '%s' not configured for project '%s'\n", serviceName, projectName)
 			fmt.Printf("Configure it first:\n")
-			fmt.Printf("  loex config %s %s [command]\n", projectName, serviceType)
+			fmt.Printf("  loex config %s %s [command]\n", projectName, serviceName)
 			os.Exit(1)
 		}
 
-		fmt.Printf("Current configuration for %s service:\n", serviceType)
+		fmt.Printf("Current configuration for %s service:\n", serviceName)
 		fmt.Printf("  Command: %s\n", service.Command)
 		fmt.Printf("  Directory: %s\n", service.Dir)
-		fmt.Print("\nEnter new command (press Enter to keep current): ")
 
-		reader := bufio.NewReader(os.Stdin)
-		newCommand, err := reader.ReadString('\n')
-		if err != nil {
-			fmt.Printf("Error reading input: %v\n", err)
-			os.Exit(1)
+		hint := "Use --command, --dir and --yes to edit without prompts."
+
+		var newCommand string
+		if cmd.Flags().Changed("command") {
+			newCommand = commandFlag
+		} else if !yesFlag {
+			newCommand = prompt("\nEnter new command (press Enter to keep current): ", hint)
 		}
-		newCommand = strings.TrimSpace(newCommand)
 		if newCommand == "" {
 			newCommand = service.Command
 		}
-
-		fmt.Print("Enter new directory (press Enter to keep current): ")
-		newDir, err := reader.ReadString('\n')
-		if err != nil {
-			fmt.Printf("Error reading input: %v\n", err)
+		if _, _, err := process.SplitCommand(newCommand); err != nil {
+			fmt.Printf("Invalid command: %v\n", err)
 			os.Exit(1)
 		}
-		newDir = strings.TrimSpace(newDir)
+
+		var newDir string
+		if cmd.Flags().Changed("dir") {
+			newDir = dirFlag
+		} else if !yesFlag {
+			newDir = prompt("Enter new directory (press Enter to keep current): ", hint)
+		}
 		if newDir == "" {
 			newDir = service.Dir
 		} else {
@@ -468,33 +758,30 @@ var configEditCmd = &cobra.Command{
 		fmt.Printf("\nNew configuration:\n")
 		fmt.Printf("  Command: %s\n", newCommand)
 		fmt.Printf("  Directory: %s\n", newDir)
-		fmt.Print("\nSave changes? (Y/n): ")
-
-		response, err := reader.ReadString('\n')
-		if err != nil {
-			fmt.Printf("Error reading input: %v\n", err)
-			os.Exit(1)
-		}
-
-		response = strings.TrimSpace(strings.ToLower(response))
-		if response != "" && response != "y" && response != "yes" {
+		if !confirm("\nSave changes? (Y/n): ", true) {
 			fmt.Printf("Changes discarded\n")
 			os.Exit(0)
 		}
 
-		project.Services[serviceType] = models.Service{
-			Type:    serviceType,
-			Command: newCommand,
-			Dir:     newDir,
+		if newCommand != service.Command {
+			service.Args = nil
 		}
-		project.Updated = time.Now()
+		service.Command = newCommand
+		service.Dir = newDir
 
-		if err := configManager.SaveProject(project); err != nil {
+		err = configManager.UpdateProject(projectName, func(current *models.Project) error {
+			if _, exists := current.Services[serviceName]; !exists {
+				return fmt.Errorf("service '%s' was removed meanwhile", serviceName)
+			}
+			current.Services[serviceName] = service
+			return nil
+		})
+		if err != nil {
 			fmt.Printf("Failed to save project: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("Service '%s' configuration updated for project '%s'\n", serviceType, projectName)
+		fmt.Printf("Service '%s' configuration updated for project '%s'\n", serviceName, projectName)
 	},
 }
 
@@ -505,13 +792,7 @@ var configDeleteCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		projectName := args[0]
-		serviceTypeStr := args[1]
-
-		serviceType := models.ServiceType(serviceTypeStr)
-		if serviceType != models.ServiceFrontend && serviceType != models.ServiceBackend && serviceType != models.ServiceDB {
-			fmt.Printf("Invalid service type '%s'. Use: frontend, backend, db\n", serviceTypeStr)
-			os.Exit(1)
-		}
+		serviceName := args[1]
 
 		configManager, err := config.NewManager()
 		if err != nil {
@@ -530,57 +811,522 @@ var configDeleteCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		service, exists := project.Services[serviceType]
+		service, exists := project.Services[serviceName]
 		if !exists {
-			fmt.Printf("Service '%s' not configured for project '%s'\n", serviceType, projectName)
+			fmt.Printf("Service '%s' not configured for project '%s'\n", serviceName, projectName)
 			os.Exit(1)
 		}
 
 		loggerManager := logger.NewManager(configManager)
 		processManager := process.NewManager(configManager, loggerManager)
-		if isRunning, _ := processManager.IsServiceRunning(projectName, serviceType); isRunning {
-			fmt.Printf("Service '%s' is currently running. Stop it first:\n", serviceType)
-			fmt.Printf("  loex stop %s %s\n", projectName, serviceType)
+		if isRunning, _ := processManager.IsServiceRunning(projectName, serviceName); isRunning {
+			fmt.Printf("Service '%s' is currently running. Stop it first:\n", serviceName)
+			fmt.Printf("  loex stop %s %s\n", projectName, serviceName)
 			os.Exit(1)
 		}
 
 		fmt.Printf("Service configuration to delete:\n")
 		fmt.Printf("  Project: %s\n", projectName)
-		fmt.Printf("  Service: %s\n", serviceType)
+		fmt.Printf("  Service: %s\n", serviceName)
 		fmt.Printf("  Command: %s\n", service.Command)
 		fmt.Printf("  Directory: %s\n", service.Dir)
-		fmt.Print("\nAre you sure you want to delete this service configuration? (y/N): ")
+		if !confirm("\nAre you sure you want to delete this service configuration? (y/N): ", false) {
+			fmt.Printf("Operation cancelled\n")
+			os.Exit(0)
+		}
 
-		reader := bufio.NewReader(os.Stdin)
-		response, err := reader.ReadString('\n')
+		err = configManager.UpdateProject(projectName, func(current *models.Project) error {
+			delete(current.Services, serviceName)
+			return nil
+		})
 		if err != nil {
-			fmt.Printf("Error reading input: %v\n", err)
+			fmt.Printf("Failed to save project: %v\n", err)
 			os.Exit(1)
 		}
 
-		response = strings.TrimSpace(strings.ToLower(response))
-		if response != "y" && response != "yes" {
-			fmt.Printf("Operation cancelled\n")
-			os.Exit(0)
+		fmt.Printf("Service '%s' deleted from project '%s'\n", serviceName, projectName)
+	},
+}
+
+var (
+	logsGlobalFlag   bool
+	logsMaxSizeFlag  int
+	logsMaxFilesFlag int
+	logsMaxAgeFlag   time.Duration
+	logsCompressFlag bool
+)
+
+var configLogsCmd = &cobra.Command{
+	Use:   "logs [project]",
+	Short: "Configure log rotation and retention",
+	Long: `Configure log rotation and retention for a project, or for all projects with
+--global. Project settings override the global ones. Without flags, the current
+policy is shown.
+
+Logs are rotated when they grow beyond --max-size or their oldest line is older
+than --max-age. Only the newest --max-files rotated files are kept, and rotated
+files older than --max-age are removed.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !logsGlobalFlag && len(args) == 0 {
+			cmd.Help()
+			return
 		}
 
-		delete(project.Services, serviceType)
-		project.Updated = time.Now()
+		configManager, err := config.NewManager()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
 
-		if err := configManager.SaveProject(project); err != nil {
-			fmt.Printf("Failed to save project: %v\n", err)
+		settings, err := configManager.LoadSettings()
+		if err != nil {
+			fmt.Printf("Failed to load settings: %v\n", err)
+			os.Exit(1)
+		}
+
+		var project *models.Project
+		if !logsGlobalFlag {
+			projectName := args[0]
+			if !configManager.ProjectExists(projectName) {
+				fmt.Printf("Project '%s' not found. Use 'loex init %s' first.\n", projectName, projectName)
+				os.Exit(1)
+			}
+			project, err = configManager.LoadProject(projectName)
+			if err != nil {
+				fmt.Printf("Failed to load project: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		flags := cmd.Flags()
+		changed := flags.Changed("max-size") || flags.Changed("max-files") || flags.Changed("max-age") || flags.Changed("compress")
+		if changed {
+			apply := func(policy **models.LogPolicy) {
+				if *policy == nil {
+					*policy = &models.LogPolicy{}
+				}
+				if flags.Changed("max-size") {
+					(*policy).MaxSizeMB = logsMaxSizeFlag
+				}
+				if flags.Changed("max-files") {
+					(*policy).MaxFiles = logsMaxFilesFlag
+				}
+				if flags.Changed("max-age") {
+					(*policy).MaxAge = models.Duration(logsMaxAgeFlag)
+				}
+				if flags.Changed("compress") {
+					compress := logsCompressFlag
+					(*policy).Compress = &compress
+				}
+			}
+
+			if logsGlobalFlag {
+				err = configManager.UpdateSettings(func(current *models.Settings) error {
+					apply(&current.Logs)
+					settings = current
+					return nil
+				})
+			} else {
+				err = configManager.UpdateProject(project.Name, func(current *models.Project) error {
+					apply(&current.Logs)
+					project = current
+					return nil
+				})
+			}
+			if err != nil {
+				fmt.Printf("Failed to save log policy: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if logsGlobalFlag {
+			fmt.Printf("Global log policy: %s\n", logger.ResolvePolicy(settings.Logs, nil))
+		} else {
+			fmt.Printf("Log policy for project '%s': %s\n", project.Name, logger.ResolvePolicy(settings.Logs, project.Logs))
+		}
+	},
+}
+
+var (
+	envSetFlag        []string
+	envUnsetFlag      []string
+	envFileFlag       []string
+	envRemoveFileFlag []string
+)
+
+var configEnvCmd = &cobra.Command{
+	Use:   "env [project] [service]",
+	Short: "Configure environment variables and env files",
+	Long: `Configure environment variables and dotenv files of a project, or of a single
+service when a service is given. Env files are relative to the service
+directory. Values may reference other variables as ${VAR}.
+
+Precedence from lowest to highest: the current environment, project env files,
+project variables, service env files, service variables.
+
+Without flags, the configured variables and files are shown. Use 'loex env' to
+see the resolved environment of a service.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectName := args[0]
+
+		configManager, err := config.NewManager()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !configManager.ProjectExists(projectName) {
+			fmt.Printf("Project '%s' not found. Use 'loex init %s' first.\n", projectName, projectName)
+			os.Exit(1)
+		}
+
+		project, err := configManager.LoadProject(projectName)
+		if err != nil {
+			fmt.Printf("Failed to load project: %v\n", err)
+			os.Exit(1)
+		}
+
+		vars, files := &project.Env, &project.EnvFiles
+		var service models.Service
+		serviceName := ""
+		if len(args) == 2 {
+			serviceName = args[1]
+			var exists bool
+			service, exists = project.Services[serviceName]
+			if !exists {
+				fmt.Printf("Service '%s' not found in project '%s'\n", serviceName, projectName)
+				os.Exit(1)
+			}
+			vars, files = &service.Env, &service.EnvFiles
+		}
+
+		for _, assignment := range envSetFlag {
+			if key, _, ok := strings.Cut(assignment, "="); !ok || !env.ValidName(key) {
+				fmt.Printf("Invalid variable '%s'. Use KEY=VALUE\n", assignment)
+				os.Exit(1)
+			}
+		}
+
+		changed := len(envSetFlag) > 0 || len(envUnsetFlag) > 0 || len(envFileFlag) > 0 || len(envRemoveFileFlag) > 0
+		if changed {
+			apply := func(vars *map[string]string, files *[]string) {
+				for _, assignment := range envSetFlag {
+					key, value, _ := strings.Cut(assignment, "=")
+					if *vars == nil {
+						*vars = make(map[string]string)
+					}
+					(*vars)[key] = value
+				}
+				for _, key := range envUnsetFlag {
+					delete(*vars, key)
+				}
+				for _, file := range envFileFlag {
+					if !slices.Contains(*files, file) {
+						*files = append(*files, file)
+					}
+				}
+				for _, file := range envRemoveFileFlag {
+					var kept []string
+					for _, f := range *files {
+						if f != file {
+							kept = append(kept, f)
+						}
+					}
+					*files = kept
+				}
+			}
+
+			err := configManager.UpdateProject(projectName, func(current *models.Project) error {
+				if serviceName == "" {
+					apply(&current.Env, &current.EnvFiles)
+					vars, files = &current.Env, &current.EnvFiles
+					return nil
+				}
+				service, exists := current.Services[serviceName]
+				if !exists {
+					return fmt.Errorf("service '%s' was removed meanwhile", serviceName)
+				}
+				apply(&service.Env, &service.EnvFiles)
+				current.Services[serviceName] = service
+				vars, files = &service.Env, &service.EnvFiles
+				return nil
+			})
+			if err != nil {
+				fmt.Printf("Failed to save project: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if serviceName != "" {
+			fmt.Printf("Environment of service '%s' in project '%s':\n", serviceName, projectName)
+		} else {
+			fmt.Printf("Environment of project '%s':\n", projectName)
+		}
+		if len(*files) == 0 && len(*vars) == 0 {
+			fmt.Printf("  (none)\n")
+			return
+		}
+		for _, file := range *files {
+			fmt.Printf("  Env file: %s\n", file)
+		}
+		keys := make([]string, 0, len(*vars))
+		for key := range *vars {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("  %s=%s\n", key, env.Mask(key, (*vars)[key]))
+		}
+	},
+}
+
+var showSecretsFlag bool
+
+var configShowCmd = &cobra.Command{
+	Use:   "show [project] [service]",
+	Short: "Show the configuration of a project or service",
+	Long: `Show the configuration of a project, or of one of its services. Use
+--output json or yaml for the stored configuration. Values of variables that
+look like secrets are masked unless --show-secrets is given.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectName := args[0]
+
+		configManager, err := config.NewManager()
+		if err != nil {
+			errorf("Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("Service '%s' deleted from project '%s'\n", serviceType, projectName)
+		if !configManager.ProjectExists(projectName) {
+			errorf("Project '%s' not found\n", projectName)
+			os.Exit(1)
+		}
+
+		project, err := configManager.LoadProject(projectName)
+		if err != nil {
+			errorf("Failed to load project: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !showSecretsFlag {
+			project.Env = maskEnv(project.Env)
+			for name, service := range project.Services {
+				service.Env = maskEnv(service.Env)
+				project.Services[name] = service
+			}
+		}
+
+		if len(args) == 2 {
+			serviceName := args[1]
+			service, exists := project.Services[serviceName]
+			if !exists {
+				errorf("Service '%s' not found in project '%s'\n", serviceName, projectName)
+				os.Exit(1)
+			}
+			if structuredOutput() {
+				printStructured(service)
+				return
+			}
+			printServiceConfig(serviceName, service)
+			return
+		}
+
+		if structuredOutput() {
+			printStructured(project)
+			return
+		}
+
+		fmt.Printf("Project: %s\n", project.Name)
+		if project.Manifest != "" {
+			fmt.Printf("Manifest: %s\n", project.Manifest)
+		}
+		for _, file := range project.EnvFiles {
+			fmt.Printf("Env file: %s\n", file)
+		}
+		for _, key := range sortedKeys(project.Env) {
+			fmt.Printf("Env: %s=%s\n", key, project.Env[key])
+		}
+		fmt.Println()
+		for _, serviceName := range project.ServiceNames() {
+			printServiceConfig(serviceName, project.Services[serviceName])
+			fmt.Println()
+		}
 	},
 }
 
+func printServiceConfig(serviceName string, service models.Service) {
+	fmt.Printf("  %s\n", serviceName)
+	if service.Kind != "" {
+		fmt.Printf("    Kind: %s\n", service.Kind)
+	}
+	if len(service.DependsOn) > 0 {
+		fmt.Printf("    Depends on: %s\n", strings.Join(service.DependsOn, ", "))
+	}
+	fmt.Printf("    Command: %s\n", service.Command)
+	if service.Shell != "" {
+		fmt.Printf("    Shell: %s\n", service.Shell)
+	}
+	fmt.Printf("    Directory: %s\n", service.Dir)
+	if len(service.Ports) > 0 {
+		fmt.Printf("    Ports: %s\n", formatPorts(service.Ports))
+	}
+	for _, name := range sortedKeys(service.NamedPorts) {
+		fmt.Printf("    Named port: %s=%s\n", name, service.NamedPorts[name])
+	}
+	for _, file := range service.EnvFiles {
+		fmt.Printf("    Env file: %s\n", file)
+	}
+	for _, key := range sortedKeys(service.Env) {
+		fmt.Printf("    Env: %s=%s\n", key, service.Env[key])
+	}
+	if service.Healthcheck != nil {
+		fmt.Printf("    Health check: %s\n", health.Describe(service.Healthcheck))
+	}
+	if service.Restart != nil {
+		fmt.Printf("    Restart policy: %s\n", service.Restart.Policy)
+	}
+	if service.StopSignal != "" {
+		fmt.Printf("    Stop signal: %s\n", service.StopSignal)
+	}
+	if service.StopTimeout > 0 {
+		fmt.Printf("    Stop timeout: %s\n", time.Duration(service.StopTimeout))
+	}
+	if service.LogFormat != "" {
+		fmt.Printf("    Log format: %s\n", service.LogFormat)
+	}
+}
+
+func maskEnv(vars map[string]string) map[string]string {
+	if vars == nil {
+		return nil
+	}
+	masked := make(map[string]string, len(vars))
+	for key, value := range vars {
+		masked[key] = env.Mask(key, value)
+	}
+	return masked
+}
+
+func sortedKeys[V any](vars map[string]V) []string {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func init() {
 	configCmd.AddCommand(configWizardCmd)
+	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configDetectCmd)
 	configCmd.AddCommand(configEditCmd)
 	configCmd.AddCommand(configDeleteCmd)
+	configCmd.AddCommand(configLogsCmd)
+	configCmd.AddCommand(configEnvCmd)
 	
+	configCmd.PersistentFlags().BoolVarP(&yesFlag, "yes", "y", false, "Answer yes to confirmations and accept defaults instead of prompting")
+
 	configCmd.Flags().StringVar(&dirFlag, "dir", "", "Directory path for the service")
-}
\ No newline at end of file
+	configCmd.Flags().StringVar(&kindFlag, "kind", "", "Optional kind label for the service (e.g. frontend, backend, db)")
+	configCmd.Flags().StringSliceVar(&dependsOnFlag, "depends-on", nil, "Services that must be started before this one (comma-separated)")
+	configCmd.Flags().StringSliceVar(&portsFlag, "ports", nil, "TCP ports the service listens on, checked before it starts (comma-separated, \"none\" removes them)")
+	configCmd.Flags().StringArrayVar(&namedPortFlag, "named-port", nil, "Port passed to the service in a variable: NAME=PORT, or NAME=auto to allocate a free one (repeatable, NAME=none removes it)")
+	configCmd.Flags().StringVar(&healthFlag, "health", "", "Readiness check: tcp:PORT, http://URL, cmd:COMMAND or log:REGEX (\"none\" removes it)")
+	configCmd.Flags().IntVar(&healthStatusFlag, "health-status", 0, "Expected HTTP status for http health checks (default 200)")
+	configCmd.Flags().DurationVar(&healthTimeoutFlag, "health-timeout", 0, "Timeout of a single health check attempt (default 5s)")
+	configCmd.Flags().DurationVar(&healthIntervalFlag, "health-interval", 0, "Delay between health check attempts (default 1s)")
+	configCmd.Flags().IntVar(&healthRetriesFlag, "health-retries", 0, "Attempts before the service is considered unhealthy (default 60)")
+	configCmd.Flags().StringVar(&restartFlag, "restart", "", "Restart policy used by 'loex daemon': never, on-failure, always")
+	configCmd.Flags().IntVar(&maxRestartsFlag, "max-restarts", 0, "Maximum restarts within --restart-window before giving up (default 5)")
+	configCmd.Flags().DurationVar(&restartWindowFlag, "restart-window", 0, "Window for --max-restarts (default 1m)")
+	configCmd.Flags().StringVar(&shellFlag, "shell", "", "Run the command with a shell and -c (\"none\" disables)")
+	configCmd.Flags().Lookup("shell").NoOptDefVal = process.DefaultShell
+	configCmd.Flags().StringVar(&stopSignalFlag, "stop-signal", "", "Signal sent to stop the service, e.g. SIGINT (default SIGTERM)")
+	configCmd.Flags().DurationVar(&stopTimeoutFlag, "stop-timeout", 0, "Time to wait for the service to exit before sending SIGKILL (default 10s)")
+	configCmd.Flags().StringVar(&logFormatFlag, "log-format", "", "Service log format: raw, timestamped (time and stream per line) or json")
+
+	configWizardCmd.Flags().StringSliceVar(&configServiceFlag, "service", nil, "Services to configure instead of prompting for names (comma-separated)")
+	configWizardCmd.Flags().StringVar(&commandFlag, "command", "", "Command of the service given with --service")
+	configWizardCmd.Flags().StringVar(&dirFlag, "dir", "", "Directory of the services")
+	configWizardCmd.Flags().StringVar(&kindFlag, "kind", "", "Optional kind label for the services")
+
+	configDetectCmd.Flags().BoolVar(&acceptAllFlag, "accept-all", false, "Use every detected command without prompting")
+	configDetectCmd.Flags().StringSliceVar(&configServiceFlag, "service", nil, "Only configure these detected services (comma-separated)")
+	configDetectCmd.Flags().StringVar(&dirFlag, "dir", "", "Directory to analyze instead of the current one")
+	configDetectCmd.Flags().BoolVarP(&recursiveFlag, "recursive", "r", false, "Also detect apps in subdirectories and workspace members")
+	configDetectCmd.Flags().IntVar(&depthFlag, "depth", detector.DefaultScanDepth, "Directory levels scanned below the current one with --recursive")
+	configDetectCmd.Flags().StringSliceVar(&ignoreFlag, "ignore", nil, "Directories to skip with --recursive, in addition to hidden ones, node_modules, vendor, target and dist (glob patterns)")
+
+	configShowCmd.Flags().BoolVar(&showSecretsFlag, "show-secrets", false, "Do not mask secret values")
+
+	configEditCmd.Flags().StringVar(&commandFlag, "command", "", "New command for the service")
+	configEditCmd.Flags().StringVar(&dirFlag, "dir", "", "New directory for the service")
+
+	configLogsCmd.Flags().BoolVar(&logsGlobalFlag, "global", false, "Change the global policy used by all projects")
+	configLogsCmd.Flags().IntVar(&logsMaxSizeFlag, "max-size", 0, "Rotate logs larger than this many megabytes (default 50)")
+	configLogsCmd.Flags().IntVar(&logsMaxFilesFlag, "max-files", 0, "Number of rotated files to keep (default 5)")
+	configLogsCmd.Flags().DurationVar(&logsMaxAgeFlag, "max-age", 0, "Rotate and remove logs older than this, e.g. 24h (default: no limit)")
+	configLogsCmd.Flags().BoolVar(&logsCompressFlag, "compress", false, "Compress rotated files with gzip")
+
+	configEnvCmd.Flags().StringArrayVar(&envSetFlag, "set", nil, "Set a variable (KEY=VALUE, repeatable)")
+	configEnvCmd.Flags().StringSliceVar(&envUnsetFlag, "unset", nil, "Remove variables")
+	configEnvCmd.Flags().StringSliceVar(&envFileFlag, "file", nil, "Add dotenv files, relative to the service directory")
+	configEnvCmd.Flags().StringSliceVar(&envRemoveFileFlag, "remove-file", nil, "Remove dotenv files")
+}
+
+// parsePorts parses the values of --ports. "none" or an empty value
+// removes the ports.
+func parsePorts(values []string) ([]int, error) {
+	var ports []int
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || value == "none" {
+			continue
+		}
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("'%s' is not a port number", value)
+		}
+		if !slices.Contains(ports, port) {
+			ports = append(ports, port)
+		}
+	}
+	return ports, nil
+}
+
+// applyNamedPorts applies the values of --named-port to a service.
+func applyNamedPorts(service *models.Service, values []string) error {
+	for _, value := range values {
+		name, spec, ok := strings.Cut(value, "=")
+		if !ok || !env.ValidName(name) {
+			return fmt.Errorf("'%s' is not NAME=PORT or NAME=auto", value)
+		}
+		if spec == "" || spec == "none" {
+			delete(service.NamedPorts, name)
+			continue
+		}
+		if err := models.PortSpec(spec).Validate(); err != nil {
+			return err
+		}
+		if service.NamedPorts == nil {
+			service.NamedPorts = make(map[string]models.PortSpec)
+		}
+		service.NamedPorts[name] = models.PortSpec(spec)
+	}
+	if len(service.NamedPorts) == 0 {
+		service.NamedPorts = nil
+	}
+	return nil
+}
+
+// relativePath returns path relative to base when it is below base.
+func relativePath(base, path string) string {
+	if rel, err := filepath.Rel(base, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
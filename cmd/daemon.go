@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/kjunh972/loex/internal/config"
+	"github.com/kjunh972/loex/internal/logger"
+	"github.com/kjunh972/loex/internal/process"
+	"github.com/kjunh972/loex/internal/supervisor"
+	"github.com/spf13/cobra"
+)
+
+var (
+	foregroundFlag bool
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon [project]",
+	Short: "Start services under a background supervisor",
+	Long: `Start all services of a project under a background supervisor that restarts
+crashed services according to their restart policy. The supervisor exits when
+no services are left running or when stopped with 'loex daemon stop'.`,
+	Args: cobra.RangeArgs(0, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectName := projectArg(args)
+
+		configManager, err := config.NewManager()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !configManager.ProjectExists(projectName) {
+			fmt.Printf("Project '%s' not found\n", projectName)
+			os.Exit(1)
+		}
+
+		loggerManager := logger.NewManager(configManager)
+		processManager := process.NewManager(configManager, loggerManager)
+
+		if foregroundFlag {
+			if err := supervisor.New(configManager, processManager, projectName).Run(); err != nil {
+				fmt.Printf("Supervisor failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if pid := processManager.SupervisorPID(projectName); pid != 0 {
+			fmt.Printf("Supervisor already running for project '%s' (PID: %d)\n", projectName, pid)
+			return
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			fmt.Printf("Failed to locate loex executable: %v\n", err)
+			os.Exit(1)
+		}
+
+		logPath := loggerManager.GetSupervisorLogPath(projectName)
+		if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+			fmt.Printf("Failed to create logs directory: %v\n", err)
+			os.Exit(1)
+		}
+		logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			fmt.Printf("Failed to open supervisor log: %v\n", err)
+			os.Exit(1)
+		}
+		defer logFile.Close()
+
+		daemon := exec.Command(executable, "daemon", projectName, "--foreground")
+		daemon.Stdout = logFile
+		daemon.Stderr = logFile
+		daemon.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+
+		if err := daemon.Start(); err != nil {
+			fmt.Printf("Failed to start supervisor: %v\n", err)
+			os.Exit(1)
+		}
+		pid := daemon.Process.Pid
+		daemon.Process.Release()
+
+		fmt.Printf("Supervisor started for project '%s' (PID: %d)\n", projectName, pid)
+		fmt.Printf("Supervisor log: %s\n", logPath)
+		fmt.Printf("Use 'loex status %s' to check service status\n", projectName)
+	},
+}
+
+var daemonStopCmd = &cobra.Command{
+	Use:   "stop [project]",
+	Short: "Stop the supervisor daemon",
+	Long:  `Stop the supervisor daemon of a project. Running services are left running; use 'loex stop' to stop them.`,
+	Args:  cobra.RangeArgs(0, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectName := projectArg(args)
+
+		configManager, err := config.NewManager()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		loggerManager := logger.NewManager(configManager)
+		processManager := process.NewManager(configManager, loggerManager)
+
+		// Supervisor checks the recorded start time and executable, so a
+		// process that took over the PID after a reboot is never signaled.
+		info, running := processManager.Supervisor(projectName)
+		if !running {
+			fmt.Printf("No supervisor running for project '%s'\n", projectName)
+			return
+		}
+		if !process.HasIdentity(info) {
+			fmt.Printf("Process %d cannot be verified as the supervisor, not signaling it; stop it manually if it is\n", info.PID)
+			os.Exit(1)
+		}
+
+		if err := syscall.Kill(info.PID, syscall.SIGTERM); err != nil {
+			fmt.Printf("Failed to stop supervisor: %v\n", err)
+			os.Exit(1)
+		}
+
+		for i := 0; i < 50 && process.IsServiceProcess(info); i++ {
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		fmt.Printf("Supervisor stopped for project '%s'\n", projectName)
+	},
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonStopCmd)
+
+	daemonCmd.Flags().BoolVar(&foregroundFlag, "foreground", false, "Run the supervisor in the foreground")
+}
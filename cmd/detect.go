@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kjunh972/loex/internal/config"
+	"github.com/kjunh972/loex/internal/detector"
+	"github.com/spf13/cobra"
+)
+
+var explainAllFlag bool
+
+var detectCmd = &cobra.Command{
+	Use:   "detect",
+	Short: "Inspect the rules used to detect services",
+	Long: `Inspect the rules 'loex config detect' and 'loex config wizard' use to propose
+services.
+
+Rules are read from .loex/detectors.d/*.yaml in the repository, then from
+~/.loex/detectors.d/*.yaml, then the built-in rules. A rule replaces rules of
+lower precedence with the same name. Every rule is evaluated: the matching rule
+with the highest confidence proposes the service of its kind and the others
+are offered as alternatives. Ties go to the rule of higher precedence.`,
+}
+
+var detectRulesCmd = &cobra.Command{
+	Use:   "rules [dir]",
+	Short: "List the active detection rules",
+	Long:  `List the detection rules active for a directory (default: the current one) in precedence order.`,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := detectDirArg(args)
+		serviceDetector := newDetector(dir)
+
+		fmt.Printf("Detection rules in precedence order:\n\n")
+		for _, rule := range serviceDetector.Rules() {
+			fmt.Printf("%s (%s)\n", rule.Name, ruleLabel(rule))
+			if rule.Description != "" {
+				fmt.Printf("    %s\n", rule.Description)
+			}
+			fmt.Printf("    Confidence: %d%%\n", rule.Confidence)
+			if rule.Command != "" {
+				fmt.Printf("    Command: %s\n", rule.Command)
+			} else {
+				fmt.Printf("    Command: derived from the project\n")
+			}
+			if conditions := rule.Match.Describe(); len(conditions) > 0 {
+				fmt.Printf("    When: %s\n", strings.Join(conditions, ", "))
+			}
+		}
+	},
+}
+
+var detectExplainCmd = &cobra.Command{
+	Use:   "explain [dir]",
+	Short: "Show which detection rules match a directory and why",
+	Long: `Evaluate every detection rule against a directory (default: the current one)
+and show the rules that matched with their conditions, and which of them
+proposes each service. --all also shows the rules that did not match.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := detectDirArg(args)
+		serviceDetector := newDetector(dir)
+
+		fmt.Printf("Analyzing %s\n\n", dir)
+		for _, name := range detector.ProcfileNames {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				fmt.Printf("Found %s: its processes are proposed instead of the rules below.\n\n", name)
+				break
+			}
+		}
+
+		hidden := 0
+		for _, match := range serviceDetector.Explain(dir) {
+			// Rules without conditions, such as the Homebrew one, only
+			// matter when they propose a service.
+			proposed := match.Matched && (match.Result != nil || len(match.Conditions) > 0)
+			if !explainAllFlag && !proposed {
+				hidden++
+				continue
+			}
+
+			switch {
+			case match.Selected:
+				fmt.Printf("✓ %s (%s): proposes %s: %s, confidence %d%%\n", match.Rule.Name, ruleLabel(match.Rule), match.Result.Name, match.Result.Command, match.Result.Confidence)
+			case match.Result != nil:
+				fmt.Printf("✓ %s (%s): alternative %s, confidence %d%%, %s ranks higher\n", match.Rule.Name, ruleLabel(match.Rule), match.Result.Command, match.Result.Confidence, match.Preferred)
+			case match.Matched:
+				fmt.Printf("✓ %s (%s): matched, but proposed no command\n", match.Rule.Name, ruleLabel(match.Rule))
+			default:
+				fmt.Printf("✗ %s (%s): not matched\n", match.Rule.Name, ruleLabel(match.Rule))
+			}
+			for _, condition := range match.Conditions {
+				mark := "✗"
+				if condition.Matched {
+					mark = "✓"
+				}
+				fmt.Printf("    %s %s\n", mark, condition.Description)
+			}
+			if match.Selected {
+				fmt.Printf("    Reason: %s\n", match.Result.DetectionReason)
+			}
+		}
+
+		if hidden > 0 {
+			fmt.Printf("\n%d other rule(s) did not match. Use --all to show them and their missing conditions.\n", hidden)
+		}
+	},
+}
+
+// chooseCandidate presents the commands detected for a service, best
+// first, and returns the one chosen. A single candidate is confirmed with
+// yes or no; among several, the answer is a number or a custom command,
+// which is returned instead. --yes and acceptAll take the first candidate.
+func chooseCandidate(candidates []detector.DetectionResult, acceptAll bool, hint string) (*detector.DetectionResult, string) {
+	if len(candidates) == 1 {
+		fmt.Printf("Auto-detected %s: %s\n", candidateLabel(candidates[0]), candidates[0].Command)
+		fmt.Printf("   Reason: %s\n", candidates[0].DetectionReason)
+		if acceptAll || confirm("Use this command? (Y/n): ", true) {
+			return &candidates[0], ""
+		}
+		return nil, ""
+	}
+
+	fmt.Printf("Detected commands, best first:\n")
+	for i, candidate := range candidates {
+		fmt.Printf("  %d) %s: %s\n", i+1, candidateLabel(candidate), candidate.Command)
+		fmt.Printf("     %s (confidence %d%%)\n", candidate.DetectionReason, candidate.Confidence)
+	}
+	if acceptAll || yesFlag {
+		return &candidates[0], ""
+	}
+
+	for {
+		answer := prompt(fmt.Sprintf("Choose a command [1-%d] (Enter for 1) or type a custom one: ", len(candidates)), hint)
+		if answer == "" {
+			return &candidates[0], ""
+		}
+		n, err := strconv.Atoi(answer)
+		if err != nil {
+			return nil, answer
+		}
+		if n >= 1 && n <= len(candidates) {
+			return &candidates[n-1], ""
+		}
+		fmt.Printf("Enter a number between 1 and %d\n", len(candidates))
+	}
+}
+
+func candidateLabel(candidate detector.DetectionResult) string {
+	if candidate.Kind != "" {
+		return string(candidate.Kind)
+	}
+	return candidate.Name
+}
+
+// newDetector returns a detector using the rules of the repository holding
+// dir, the user rules and the built-in rules.
+func newDetector(dir string) *detector.ServiceDetector {
+	configManager, err := config.NewManager()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	rules, err := detector.LoadRules(configManager.GetDetectorsPath(), dir)
+	if err != nil {
+		fmt.Printf("Failed to load detection rules: %v\n", err)
+		os.Exit(1)
+	}
+	return detector.NewWithRules(rules)
+}
+
+func detectDirArg(args []string) string {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		fmt.Printf("Invalid directory path: %v\n", err)
+		os.Exit(1)
+	}
+	if info, err := os.Stat(absDir); err != nil || !info.IsDir() {
+		fmt.Printf("%s is not a directory\n", absDir)
+		os.Exit(1)
+	}
+	return absDir
+}
+
+// ruleLabel describes the kind and source of a rule.
+func ruleLabel(rule detector.Rule) string {
+	kind := string(rule.Kind)
+	if kind == "" {
+		kind = "service " + rule.ServiceName()
+	}
+	return kind + ", " + rule.Source
+}
+
+func init() {
+	detectCmd.AddCommand(detectRulesCmd)
+	detectCmd.AddCommand(detectExplainCmd)
+
+	detectExplainCmd.Flags().BoolVar(&explainAllFlag, "all", false, "Also show the rules that did not match")
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kjunh972/loex/internal/config"
+	"github.com/kjunh972/loex/internal/env"
+	"github.com/kjunh972/loex/internal/process"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envAllFlag         bool
+	envShowSecretsFlag bool
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env [project] service",
+	Short: "Show the resolved environment of a service",
+	Long: `Show the environment a service is started with, after loading env files and
+interpolating variables. Values of variables that look like secrets are masked.
+Only variables defined by loex are shown unless --all is given.
+
+Without a project, the project declared in the nearest loex.yaml is used.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		// The service is always the last argument.
+		projectName := projectArg(args[:len(args)-1])
+		serviceName := args[len(args)-1]
+
+		configManager, err := config.NewManager()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !configManager.ProjectExists(projectName) {
+			fmt.Printf("Project '%s' not found\n", projectName)
+			os.Exit(1)
+		}
+
+		project, err := configManager.LoadProject(projectName)
+		if err != nil {
+			fmt.Printf("Failed to load project: %v\n", err)
+			os.Exit(1)
+		}
+
+		service, exists := project.Services[serviceName]
+		if !exists {
+			fmt.Printf("Service '%s' not found in project '%s'\n", serviceName, projectName)
+			os.Exit(1)
+		}
+
+		var allocated map[string]map[string]int
+		if pids, err := configManager.LoadProjectPIDs(projectName); err == nil {
+			allocated = pids.Ports
+		}
+
+		environment, err := env.ForService(project, service, process.PortEnv(project, serviceName, allocated))
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, key := range environment.Keys() {
+			source := environment.Sources[key]
+			if source == env.SourceInherited && !envAllFlag {
+				continue
+			}
+
+			value := environment.Vars[key]
+			if !envShowSecretsFlag {
+				value = env.Mask(key, value)
+			}
+			fmt.Printf("%s=%s    # %s\n", key, value, source)
+		}
+	},
+}
+
+func init() {
+	envCmd.Flags().BoolVar(&envAllFlag, "all", false, "Also show variables inherited from the current environment")
+	envCmd.Flags().BoolVar(&envShowSecretsFlag, "show-secrets", false, "Do not mask secret values")
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kjunh972/loex/internal/config"
+	"github.com/kjunh972/loex/internal/detector"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFileFlag  string
+	exportForceFlag bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export services for other tools",
+}
+
+var exportProcfileCmd = &cobra.Command{
+	Use:   "procfile [project]",
+	Short: "Write the project's services to a Procfile",
+	Long: `Write one Procfile entry per service, for use with foreman, overmind and
+similar tools. Services outside the Procfile's directory are run with a cd and
+environment variables are set inline. Env files are listed in a comment at the
+top, to be passed with -e. Variables that look like secrets, or that reference
+other variables, are not written to the Procfile; a warning names them.
+
+Use --file - to print the Procfile instead of writing it.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectName := projectArg(args)
+
+		configManager, err := config.NewManager()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !configManager.ProjectExists(projectName) {
+			fmt.Printf("Project '%s' not found\n", projectName)
+			os.Exit(1)
+		}
+
+		project, err := configManager.LoadProject(projectName)
+		if err != nil {
+			fmt.Printf("Failed to load project: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(project.Services) == 0 {
+			fmt.Printf("No services configured for project '%s'\n", projectName)
+			os.Exit(1)
+		}
+
+		if exportFileFlag == "-" {
+			cwd, _ := os.Getwd()
+			content, warnings := detector.FormatProcfile(project, cwd)
+			fmt.Print(content)
+			// Warnings go to stderr so stdout stays a valid Procfile.
+			for _, warning := range warnings {
+				fmt.Fprintf(os.Stderr, "Not exported: %s\n", warning)
+			}
+			return
+		}
+
+		path, err := filepath.Abs(exportFileFlag)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if _, err := os.Stat(path); err == nil && !exportForceFlag {
+			fmt.Printf("%s already exists (use --force to overwrite)\n", path)
+			os.Exit(1)
+		}
+
+		content, warnings := detector.FormatProcfile(project, filepath.Dir(path))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			fmt.Printf("Failed to write Procfile: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Wrote %d service(s) to %s\n", len(project.Services), path)
+		if len(warnings) > 0 {
+			fmt.Printf("\nNot exported:\n")
+			for _, warning := range warnings {
+				fmt.Printf("  - %s\n", warning)
+			}
+		}
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportProcfileCmd)
+
+	exportProcfileCmd.Flags().StringVar(&exportFileFlag, "file", "Procfile", "Path of the Procfile to write, or - for stdout")
+	exportProcfileCmd.Flags().BoolVar(&exportForceFlag, "force", false, "Overwrite an existing file")
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kjunh972/loex/internal/config"
+	"github.com/kjunh972/loex/internal/detector"
+	"github.com/kjunh972/loex/internal/health"
+	"github.com/kjunh972/loex/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var replaceFlag bool
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import services from other tools",
+}
+
+var importComposeCmd = &cobra.Command{
+	Use:   "compose [file] [project]",
+	Short: "Import services from a docker compose file",
+	Long: `Create one loex service per docker compose service. Each service runs
+'docker compose up --no-deps <service>' in the foreground, so loex controls the
+start order. depends_on, published ports, environment, env files and
+healthchecks are carried over; settings that cannot be are reported.
+
+The project is created if it does not exist. Existing services are kept unless
+--replace is given.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		composePath := args[0]
+		projectName := args[1]
+
+		if strings.ContainsAny(projectName, " \t\n\r/\\:<>|*?") {
+			fmt.Printf("Project name contains invalid characters. Use only letters, numbers, hyphens, and underscores.\n")
+			os.Exit(1)
+		}
+
+		imported, err := detector.ParseCompose(composePath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		configManager, err := config.NewManager()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var project *models.Project
+		if configManager.ProjectExists(projectName) {
+			project, err = configManager.LoadProject(projectName)
+			if err != nil {
+				fmt.Printf("Failed to load project: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			project = &models.Project{
+				Name:     projectName,
+				Services: make(map[string]models.Service),
+				Created:  time.Now(),
+				Updated:  time.Now(),
+			}
+		}
+
+		var added []string
+		for _, serviceName := range sortedServiceNames(imported.Services) {
+			if err := validateServiceName(serviceName); err != nil {
+				fmt.Printf("Skipping service: %v\n", err)
+				continue
+			}
+			if _, exists := project.Services[serviceName]; exists && !replaceFlag {
+				fmt.Printf("Skipping '%s': service already exists (use --replace to overwrite)\n", serviceName)
+				continue
+			}
+			project.Services[serviceName] = imported.Services[serviceName]
+			added = append(added, serviceName)
+		}
+
+		if err := project.ValidateDependencies(); err != nil {
+			fmt.Printf("Invalid dependencies: %v\n", err)
+			os.Exit(1)
+		}
+
+		err = configManager.UpdateProject(projectName, func(current *models.Project) error {
+			for _, serviceName := range added {
+				current.Services[serviceName] = project.Services[serviceName]
+			}
+			project = current
+			return current.ValidateDependencies()
+		})
+		if err != nil {
+			fmt.Printf("Failed to save project: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, serviceName := range added {
+			service := project.Services[serviceName]
+			fmt.Printf("Imported %s\n", serviceName)
+			if len(service.DependsOn) > 0 {
+				fmt.Printf("    Depends on: %s\n", strings.Join(service.DependsOn, ", "))
+			}
+			if len(service.Ports) > 0 {
+				fmt.Printf("    Ports: %s\n", formatPorts(service.Ports))
+			}
+			if service.Healthcheck != nil {
+				fmt.Printf("    Health check: %s\n", health.Describe(service.Healthcheck))
+			}
+		}
+
+		if len(imported.Warnings) > 0 {
+			fmt.Printf("\nNot imported:\n")
+			for _, warning := range imported.Warnings {
+				fmt.Printf("  - %s\n", warning)
+			}
+		}
+
+		fmt.Printf("\n%d service(s) imported into project '%s'\n", len(added), projectName)
+		fmt.Printf("Use 'loex start %s' to start them\n", projectName)
+	},
+}
+
+func sortedServiceNames(services map[string]models.Service) []string {
+	project := models.Project{Services: services}
+	return project.ServiceNames()
+}
+
+func init() {
+	importCmd.AddCommand(importComposeCmd)
+
+	importComposeCmd.Flags().BoolVar(&replaceFlag, "replace", false, "Overwrite services that already exist in the project")
+}
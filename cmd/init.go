@@ -43,7 +43,7 @@ var initCmd = &cobra.Command{
 
 		project := &models.Project{
 			Name:     projectName,
-			Services: make(map[models.ServiceType]models.Service),
+			Services: make(map[string]models.Service),
 			Created:  time.Now(),
 			Updated:  time.Now(),
 		}
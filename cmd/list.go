@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/kjunh972/loex/internal/config"
@@ -18,7 +19,7 @@ var listCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		configManager, err := config.NewManager()
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
+			errorf("Error: %v\n", err)
 			os.Exit(1)
 		}
 
@@ -26,11 +27,37 @@ var listCmd = &cobra.Command{
 			projectName := args[0]
 			project, err := configManager.LoadProject(projectName)
 			if err != nil {
-				fmt.Printf("Project '%s' not found\n", projectName)
+				errorf("Project '%s' not found\n", projectName)
 				os.Exit(1)
 			}
 
+			if structuredOutput() {
+				logManager := logger.NewManager(configManager)
+				processManager := process.NewManager(configManager, logManager)
+
+				result := ProjectStatus{Project: projectName, Total: len(project.Services), Services: []ServiceStatus{}}
+				allocated := processManager.AllocatedPorts(projectName)
+				for _, serviceName := range project.ServiceNames() {
+					status, err := processManager.GetServiceStatus(projectName, serviceName)
+					if err != nil {
+						status = "unknown"
+					}
+					info, _ := processManager.GetProcessDetails(projectName, serviceName)
+					if process.IsActive(status) {
+						result.Running++
+					}
+					serviceStatus := newServiceStatus(serviceName, project.Services[serviceName], status, info)
+					setPorts(&serviceStatus, project.Services[serviceName], allocated[serviceName])
+					result.Services = append(result.Services, serviceStatus)
+				}
+				printStructured(result)
+				return
+			}
+
 			fmt.Printf("Project: %s\n", projectName)
+			if project.Manifest != "" {
+				fmt.Printf("Manifest: %s\n", project.Manifest)
+			}
 			fmt.Printf("Created: %s\n", project.Created.Format("2006-01-02 15:04:05"))
 			fmt.Printf("Updated: %s\n", project.Updated.Format("2006-01-02 15:04:05"))
 			fmt.Printf("Services: %d\n\n", len(project.Services))
@@ -39,8 +66,9 @@ var listCmd = &cobra.Command{
 				logManager := logger.NewManager(configManager)
 				processManager := process.NewManager(configManager, logManager)
 				
-				for serviceType, service := range project.Services {
-					status, err := processManager.GetServiceStatus(projectName, serviceType)
+				for _, serviceName := range project.ServiceNames() {
+					service := project.Services[serviceName]
+					status, err := processManager.GetServiceStatus(projectName, serviceName)
 					if err != nil {
 						status = "unknown"
 					}
@@ -49,13 +77,29 @@ var listCmd = &cobra.Command{
 					switch status {
 					case "running":
 						statusDisplay = "running ●"
+					case "ready":
+						statusDisplay = "ready ●"
+					case "starting":
+						statusDisplay = "starting ◐"
+					case "unhealthy":
+						statusDisplay = "unhealthy ✗"
+					case "restarting":
+						statusDisplay = "restarting ◐"
+					case "exited":
+						statusDisplay = "exited ✗"
 					case "stopped":
 						statusDisplay = "stopped ○"
 					default:
 						statusDisplay = "unknown ?"
 					}
 					
-					fmt.Printf("  %s: %s\n", serviceType, statusDisplay)
+					fmt.Printf("  %s: %s\n", serviceName, statusDisplay)
+					if service.Kind != "" {
+						fmt.Printf("    Kind: %s\n", service.Kind)
+					}
+					if len(service.DependsOn) > 0 {
+						fmt.Printf("    Depends on: %s\n", strings.Join(service.DependsOn, ", "))
+					}
 					fmt.Printf("    Command: %s\n", service.Command)
 					fmt.Printf("    Directory: %s\n", service.Dir)
 					fmt.Println()
@@ -66,10 +110,30 @@ var listCmd = &cobra.Command{
 
 		projects, err := configManager.ListProjects()
 		if err != nil {
-			fmt.Printf("Failed to list projects: %v\n", err)
+			errorf("Failed to list projects: %v\n", err)
 			os.Exit(1)
 		}
 
+		if structuredOutput() {
+			summaries := []ProjectSummary{}
+			for _, projectName := range projects {
+				project, err := configManager.LoadProject(projectName)
+				if err != nil {
+					errorf("Failed to load project '%s': %v\n", projectName, err)
+					os.Exit(1)
+				}
+				summaries = append(summaries, ProjectSummary{
+					Name:     projectName,
+					Manifest: project.Manifest,
+					Services: len(project.Services),
+					Created:  project.Created,
+					Updated:  project.Updated,
+				})
+			}
+			printStructured(summaries)
+			return
+		}
+
 		if len(projects) == 0 {
 			fmt.Println("No projects found")
 			fmt.Println("Use 'loex init [project]' to create your first project")
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/kjunh972/loex/internal/config"
+	"github.com/kjunh972/loex/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tailFlag   int
+	followFlag bool
+	sinceFlag  string
+	untilFlag  string
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs [project] [service...]",
+	Short: "Show service logs",
+	Long: `Show the logs of a project's services. Without services, the logs of all
+services are merged into a single view ordered by time, each line prefixed with
+the service name.
+
+Rotated log files, compressed or not, are read as well when --tail or --since
+reach back past the current file.
+
+With --output json or yaml, each line is printed as a record with the service,
+the time of the line when known, and its text.
+
+--since and --until accept a duration relative to now (e.g. 10m, 2h) or a
+timestamp (e.g. 2024-01-02T15:04:05Z, "2024-01-02 15:04:05", 2024-01-02).
+They need lines with timestamps: use the timestamped or json log format
+(loex config <project> <service> <command> --log-format timestamped), or a
+program that prints its own times. Raw logs without timestamps are rejected.
+In the merged view, such lines take the time their service was started.`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectName := projectArg(args)
+
+		configManager, err := config.NewManager()
+		if err != nil {
+			errorf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !configManager.ProjectExists(projectName) {
+			errorf("Project '%s' not found\n", projectName)
+			os.Exit(1)
+		}
+
+		project, err := configManager.LoadProject(projectName)
+		if err != nil {
+			errorf("Failed to load project: %v\n", err)
+			os.Exit(1)
+		}
+
+		services := project.ServiceNames()
+		if len(args) > 1 {
+			services = args[1:]
+			for _, serviceName := range services {
+				if _, exists := project.Services[serviceName]; !exists {
+					errorf("Service '%s' not found in project '%s'\n", serviceName, projectName)
+					os.Exit(1)
+				}
+			}
+		}
+
+		if len(services) == 0 {
+			errorf("No services configured for project '%s'\n", projectName)
+			os.Exit(1)
+		}
+
+		if followFlag && untilFlag != "" {
+			errorf("Error: --until cannot be used with --follow\n")
+			os.Exit(1)
+		}
+
+		opts := logger.ReadOptions{Tail: tailFlag, WithTimes: len(services) > 1 || structuredOutput()}
+		if sinceFlag != "" {
+			if opts.Since, err = parseTimeFlag(sinceFlag); err != nil {
+				errorf("Invalid --since value: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if untilFlag != "" {
+			if opts.Until, err = parseTimeFlag(untilFlag); err != nil {
+				errorf("Invalid --until value: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		loggerManager := logger.NewManager(configManager)
+
+		prefixes := make(map[string]string)
+		if len(services) > 1 && !structuredOutput() {
+			width := 0
+			for _, serviceName := range services {
+				if len(serviceName) > width {
+					width = len(serviceName)
+				}
+			}
+			useColor := isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+			for i, serviceName := range services {
+				prefix := fmt.Sprintf("[%-*s] ", width, serviceName)
+				if useColor {
+					prefix = upColors[i%len(upColors)] + prefix + colorReset
+				}
+				prefixes[serviceName] = prefix
+			}
+		}
+
+		var groups [][]logger.Line
+		for _, serviceName := range services {
+			lines, err := logger.ReadLines(loggerManager.GetLogPath(projectName, serviceName), serviceName, opts)
+			if errors.Is(err, logger.ErrNoTimestamps) {
+				errorf("Error: --since and --until need timestamps, but the %s log has none; use --log-format timestamped or json for it\n", serviceName)
+				os.Exit(1)
+			}
+			if err != nil {
+				errorf("Failed to read %s logs: %v\n", serviceName, err)
+				os.Exit(1)
+			}
+			groups = append(groups, lines)
+		}
+
+		for _, line := range logger.MergeLines(groups, tailFlag) {
+			if structuredOutput() {
+				printRecord(newLogRecord(line.Service, line.Time, line.Text))
+				continue
+			}
+			fmt.Printf("%s%s\n", prefixes[line.Service], line.Text)
+		}
+
+		if !followFlag {
+			return
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		var outputMu sync.Mutex
+		var wg sync.WaitGroup
+		for _, serviceName := range services {
+			prefix := prefixes[serviceName]
+			logPath := loggerManager.GetLogPath(projectName, serviceName)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				logger.Follow(ctx, logPath, func(text string) {
+					outputMu.Lock()
+					defer outputMu.Unlock()
+					if structuredOutput() {
+						t, _ := logger.ParseTimestamp(text)
+						printRecord(newLogRecord(serviceName, t, text))
+						return
+					}
+					fmt.Printf("%s%s\n", prefix, text)
+				})
+			}()
+		}
+		wg.Wait()
+	},
+}
+
+func newLogRecord(service string, t time.Time, text string) LogRecord {
+	record := LogRecord{Service: service, Text: text}
+	if !t.IsZero() {
+		record.Time = &t
+	}
+	return record
+}
+
+// parseTimeFlag parses a --since/--until value, either a duration before
+// now or an absolute timestamp.
+func parseTimeFlag(value string) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("'%s' is neither a duration nor a timestamp", value)
+}
+
+func init() {
+	logsCmd.Flags().IntVarP(&tailFlag, "tail", "n", 100, "Number of lines to show from the end of the logs (0 for all)")
+	logsCmd.Flags().BoolVarP(&followFlag, "follow", "f", false, "Keep printing new log lines as they are written")
+	logsCmd.Flags().StringVar(&sinceFlag, "since", "", "Show lines written after this time")
+	logsCmd.Flags().StringVar(&untilFlag, "until", "", "Show lines written before this time")
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/kjunh972/loex/internal/health"
+	"github.com/kjunh972/loex/internal/process"
+	"github.com/kjunh972/loex/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats of the global --output flag.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+// Exit codes of 'loex status', so scripts can test the state of a project.
+// In json and yaml mode errors are printed to stderr, see errorf, so stdout
+// only ever holds structured output.
+const (
+	ExitAllRunning = 0
+	ExitError      = 1
+	ExitPartial    = 3 // some services are not running
+	ExitStopped    = 4 // no service is running
+	ExitUnhealthy  = 5 // all services are running but some are unhealthy
+)
+
+var outputFlag string
+
+// ProjectSummary is an entry of 'loex list --output json|yaml'.
+type ProjectSummary struct {
+	Name     string    `json:"name"`
+	Manifest string    `json:"manifest,omitempty"`
+	Services int       `json:"services"`
+	Created  time.Time `json:"created"`
+	Updated  time.Time `json:"updated"`
+}
+
+// ProjectStatus is the output of 'loex status' and 'loex list [project]'.
+type ProjectStatus struct {
+	Project string `json:"project"`
+	// SupervisorPID is set while 'loex daemon' supervises the project.
+	SupervisorPID int             `json:"supervisor_pid,omitempty"`
+	Running       int             `json:"running"`
+	Total         int             `json:"total"`
+	Services      []ServiceStatus `json:"services"`
+}
+
+// ServiceStatus describes a service and its process. PID and StartTime are
+// only set while the service is running. Health is "starting", "healthy" or
+// "unhealthy" for running services with a health check. NamedPorts holds
+// the named ports with auto ports as allocated, and Listening lists the
+// ports something listens on.
+type ServiceStatus struct {
+	Name         string         `json:"name"`
+	Kind         string         `json:"kind,omitempty"`
+	Status       string         `json:"status"`
+	Command      string         `json:"command"`
+	Dir          string         `json:"dir"`
+	DependsOn    []string       `json:"depends_on,omitempty"`
+	Ports        []int          `json:"ports,omitempty"`
+	NamedPorts   map[string]int `json:"named_ports,omitempty"`
+	Listening    []int          `json:"listening,omitempty"`
+	PID          int            `json:"pid,omitempty"`
+	StartTime    *time.Time     `json:"start_time,omitempty"`
+	HealthCheck  string         `json:"health_check,omitempty"`
+	Health       string         `json:"health,omitempty"`
+	Restart      string         `json:"restart,omitempty"`
+	Restarts     int            `json:"restarts"`
+	LastExitCode *int           `json:"last_exit_code,omitempty"`
+	NextRestart  *time.Time     `json:"next_restart,omitempty"`
+}
+
+// LogRecord is a log line of 'loex logs --output json|yaml'. Time is
+// omitted when the line has no timestamp.
+type LogRecord struct {
+	Service string     `json:"service"`
+	Time    *time.Time `json:"time,omitempty"`
+	Text    string     `json:"text"`
+}
+
+// errorf prints an error message: to stderr in json and yaml mode, so it
+// does not end up in the output parsed by scripts, and to stdout otherwise.
+func errorf(format string, args ...interface{}) {
+	if structuredOutput() {
+		fmt.Fprintf(os.Stderr, format, args...)
+		return
+	}
+	fmt.Printf(format, args...)
+}
+
+func structuredOutput() bool {
+	return outputFlag == OutputJSON || outputFlag == OutputYAML
+}
+
+func validateOutput() error {
+	switch outputFlag {
+	case OutputTable, OutputJSON, OutputYAML:
+		return nil
+	}
+	return fmt.Errorf("invalid output format '%s'. Use: table, json, yaml", outputFlag)
+}
+
+// printStructured prints v as indented JSON or as YAML. YAML is produced
+// from the JSON encoding so both formats use the same field names.
+func printStructured(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		errorf("Error: %v\n", err)
+		os.Exit(ExitError)
+	}
+
+	if outputFlag == OutputYAML {
+		if data, err = jsonToYAML(data); err != nil {
+			errorf("Error: %v\n", err)
+			os.Exit(ExitError)
+		}
+		fmt.Print(string(data))
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// printRecord prints one record of a stream: a line of JSON, or an item of
+// a YAML sequence.
+func printRecord(v interface{}) {
+	data, err := json.Marshal(v)
+	if err == nil && outputFlag == OutputYAML {
+		data, err = jsonToYAML([]byte("[" + string(data) + "]"))
+	}
+	if err != nil {
+		errorf("Error: %v\n", err)
+		os.Exit(ExitError)
+	}
+	fmt.Println(string(bytes.TrimRight(data, "\n")))
+}
+
+func jsonToYAML(data []byte) ([]byte, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc)
+}
+
+// newServiceStatus describes a service from its configuration, status and
+// process info, which may be nil.
+func newServiceStatus(name string, service models.Service, status string, info *models.ProcessInfo) ServiceStatus {
+	s := ServiceStatus{
+		Name:      name,
+		Kind:      string(service.Kind),
+		Status:    status,
+		Command:   service.Command,
+		Dir:       service.Dir,
+		DependsOn: service.DependsOn,
+		Ports:     service.Ports,
+	}
+
+	if service.Healthcheck != nil {
+		s.HealthCheck = health.Describe(service.Healthcheck)
+		switch status {
+		case process.StatusStarting:
+			s.Health = "starting"
+		case process.StatusReady:
+			s.Health = "healthy"
+		case process.StatusUnhealthy:
+			s.Health = "unhealthy"
+		}
+	}
+	if service.Restart != nil {
+		s.Restart = service.Restart.Policy
+	}
+
+	if info != nil {
+		if process.IsActive(status) {
+			s.PID = info.PID
+			startTime := info.StartTime
+			s.StartTime = &startTime
+		}
+		if status == process.StatusRestarting {
+			s.NextRestart = info.NextRestart
+		}
+		s.Restarts = info.Restarts
+		s.LastExitCode = info.LastExitCode
+	}
+	return s
+}
+
+// setPorts sets the named ports of a service, given the ports allocated
+// to it, and checks which of its ports are listening.
+func setPorts(s *ServiceStatus, service models.Service, allocated map[string]int) {
+	s.NamedPorts = process.NamedPorts(service, allocated)
+
+	ports := slices.Clone(s.Ports)
+	for _, port := range s.NamedPorts {
+		ports = append(ports, port)
+	}
+	s.Listening = nil
+	for _, port := range ports {
+		if process.PortListening(port) && !slices.Contains(s.Listening, port) {
+			s.Listening = append(s.Listening, port)
+		}
+	}
+	slices.Sort(s.Listening)
+}
+
+// statusExitCode returns the exit code of 'loex status' for the services.
+func statusExitCode(services []ServiceStatus) int {
+	running := 0
+	unhealthy := false
+	for _, s := range services {
+		if process.IsActive(s.Status) {
+			running++
+		}
+		if s.Status == process.StatusUnhealthy {
+			unhealthy = true
+		}
+	}
+
+	switch {
+	case running == 0:
+		return ExitStopped
+	case running < len(services):
+		return ExitPartial
+	case unhealthy:
+		return ExitUnhealthy
+	}
+	return ExitAllRunning
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestStatusExitCode(t *testing.T) {
+	tests := []struct {
+		statuses []string
+		expected int
+	}{
+		{[]string{"running", "ready"}, ExitAllRunning},
+		{[]string{"running", "stopped"}, ExitPartial},
+		{[]string{"stopped", "exited"}, ExitStopped},
+		{[]string{"ready", "unhealthy"}, ExitUnhealthy},
+		{[]string{"unhealthy", "stopped"}, ExitPartial},
+	}
+
+	for _, test := range tests {
+		var services []ServiceStatus
+		for _, status := range test.statuses {
+			services = append(services, ServiceStatus{Status: status})
+		}
+		if code := statusExitCode(services); code != test.expected {
+			t.Errorf("%v: expected exit code %d, got %d", test.statuses, test.expected, code)
+		}
+	}
+}
+
+func TestJSONToYAML(t *testing.T) {
+	data, err := jsonToYAML([]byte(`{"name":"api","ports":[8080],"start_time":"2024-01-02T15:04:05Z"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "name: api\nports:\n    - 8080\nstart_time: \"2024-01-02T15:04:05Z\"\n"
+	if string(data) != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, data)
+	}
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	yesFlag           bool
+	commandFlag       string
+	configServiceFlag []string
+	acceptAllFlag     bool
+)
+
+var stdinReader *bufio.Reader
+
+// prompt prints a question and returns the trimmed answer. When stdin is not
+// a terminal it exits with hint, which names the flags that answer the
+// question, instead of waiting for input that never comes.
+func prompt(question, hint string) string {
+	if !isTerminal(os.Stdin) {
+		fmt.Printf("%s\nInput is required but stdin is not a terminal. %s\n", strings.TrimSpace(question), hint)
+		os.Exit(1)
+	}
+	if stdinReader == nil {
+		stdinReader = bufio.NewReader(os.Stdin)
+	}
+
+	fmt.Print(question)
+	answer, err := stdinReader.ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Printf("Error reading input: %v\n", err)
+		os.Exit(1)
+	}
+	return strings.TrimSpace(answer)
+}
+
+// confirm asks a yes/no question. --yes answers it without prompting.
+func confirm(question string, defaultYes bool) bool {
+	if yesFlag {
+		return true
+	}
+
+	answer := strings.ToLower(prompt(question, "Use --yes to confirm."))
+	if answer == "" {
+		return defaultYes
+	}
+	return answer == "y" || answer == "yes"
+}
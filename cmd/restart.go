@@ -7,7 +7,6 @@ import (
 	"github.com/kjunh972/loex/internal/config"
 	"github.com/kjunh972/loex/internal/logger"
 	"github.com/kjunh972/loex/internal/process"
-	"github.com/kjunh972/loex/pkg/models"
 	"github.com/spf13/cobra"
 )
 
@@ -15,9 +14,9 @@ var restartCmd = &cobra.Command{
 	Use:   "restart [project]",
 	Short: "Restart all services for a project",
 	Long:  `Stop and start all services for a project.`,
-	Args:  cobra.ExactArgs(1),
+	Args:  cobra.RangeArgs(0, 1),
 	Run: func(cmd *cobra.Command, args []string) {
-		projectName := args[0]
+		projectName := projectArg(args)
 
 		configManager, err := config.NewManager()
 		if err != nil {
@@ -48,31 +47,39 @@ var restartCmd = &cobra.Command{
 
 		loggerManager := logger.NewManager(configManager)
 		processManager := process.NewManager(configManager, loggerManager)
+		processManager.SetPortConflictHandler(offerToStopPortOwner(processManager))
 
 		fmt.Printf("Restarting services for project '%s'...\n", projectName)
 
-		// Stop all running services
-		serviceOrder := []models.ServiceType{models.ServiceFrontend, models.ServiceBackend, models.ServiceDB}
-		for _, serviceType := range serviceOrder {
-			if _, exists := project.Services[serviceType]; exists {
-				if isRunning, _ := processManager.IsServiceRunning(projectName, serviceType); isRunning {
-					fmt.Printf("Stopping %s service...\n", serviceType)
-					if err := processManager.StopService(projectName, serviceType); err != nil {
-						fmt.Printf("Failed to stop %s service: %v\n", serviceType, err)
+		stopLevels, err := project.StopLevels(project.ServiceNames())
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Stop running services, dependents first
+		for _, level := range stopLevels {
+			for _, serviceName := range level {
+				if isRunning, _ := processManager.IsServiceRunning(projectName, serviceName); isRunning {
+					fmt.Printf("Stopping %s service...\n", serviceName)
+					if err := processManager.StopService(projectName, serviceName); err != nil {
+						fmt.Printf("Failed to stop %s service: %v\n", serviceName, err)
 					}
 				}
 			}
 		}
 
-		// Start services in order
-		serviceStartOrder := []models.ServiceType{models.ServiceDB, models.ServiceBackend, models.ServiceFrontend}
+		// Start services in dependency order
+		failed, err := processManager.StartServices(project, project.ServiceNames(), nil)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
 		var errors []string
-		for _, serviceType := range serviceStartOrder {
-			if _, exists := project.Services[serviceType]; exists {
-				fmt.Printf("Starting %s service...\n", serviceType)
-				if err := processManager.StartService(projectName, serviceType); err != nil {
-					errors = append(errors, fmt.Sprintf("%s: %v", serviceType, err))
-				}
+		for _, serviceName := range project.ServiceNames() {
+			if err, ok := failed[serviceName]; ok {
+				errors = append(errors, fmt.Sprintf("%s: %v", serviceName, err))
 			}
 		}
 
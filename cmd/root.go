@@ -16,6 +16,9 @@ var rootCmd = &cobra.Command{
 	Use:   "loex",
 	Short: "Local development environment manager",
 	Long: `Loex is a CLI tool for managing and running your local frontend, backend, and database services easily.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateOutput()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		if versionFlag, _ := cmd.Flags().GetBool("version"); versionFlag {
 			fmt.Printf("loex version %s\n", version)
@@ -50,15 +53,24 @@ func init() {
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(restartCmd)
+	rootCmd.AddCommand(daemonCmd)
+	rootCmd.AddCommand(upCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(envCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(removeCmd)
 	rootCmd.AddCommand(renameCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(detectCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(exportCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(runServiceCmd)
 	
 	rootCmd.Flags().BoolP("version", "v", false, "Print version information")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", OutputTable, "Output format of status, list, config show and logs: table, json, yaml")
 }
 
 
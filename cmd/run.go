@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kjunh972/loex/internal/config"
+	"github.com/kjunh972/loex/internal/logger"
+	"github.com/kjunh972/loex/internal/process"
+	"github.com/spf13/cobra"
+)
+
+// runServiceCmd is started by the process manager for every service and
+// runs the service command with its output written to the rotating log.
+var runServiceCmd = &cobra.Command{
+	Use:                process.ShimCommand + " [project] [service] [--log-format=FORMAT] -- [command...]",
+	Short:              "Run a service command (used internally)",
+	Hidden:             true,
+	DisableFlagParsing: true,
+	Args:               cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectName, serviceName, argv := args[0], args[1], args[2:]
+		logFormat := ""
+		for len(argv) > 0 && strings.HasPrefix(argv[0], "--") {
+			option := argv[0]
+			argv = argv[1:]
+			if option == "--" {
+				break
+			}
+			if value, ok := strings.CutPrefix(option, "--log-format="); ok {
+				logFormat = value
+			}
+		}
+
+		configManager, err := config.NewManager()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		loggerManager := logger.NewManager(configManager)
+		os.Exit(process.RunShim(loggerManager, projectName, serviceName, logFormat, argv))
+	},
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kjunh972/loex/internal/config"
+)
+
+func validateServiceName(name string) error {
+	if name == "" {
+		return fmt.Errorf("service name cannot be empty")
+	}
+	if strings.ContainsAny(name, " \t\n\r/\\:<>|*?") {
+		return fmt.Errorf("service name '%s' contains invalid characters. Use only letters, numbers, hyphens, and underscores", name)
+	}
+	return nil
+}
+
+// projectArg returns the project named by the first argument. Without
+// arguments, the project is taken from the loex.yaml manifest found by
+// walking up from the current directory.
+func projectArg(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+
+	configManager, err := config.NewManager()
+	if err != nil {
+		errorf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		errorf("Failed to get current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	projectName, err := configManager.DiscoverProject(cwd)
+	if err != nil {
+		errorf("No project given and %v\n", err)
+		os.Exit(1)
+	}
+	return projectName
+}
@@ -3,26 +3,27 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"time"
+	"sync"
 
 	"github.com/spf13/cobra"
 	"github.com/kjunh972/loex/internal/config"
 	"github.com/kjunh972/loex/internal/logger"
 	"github.com/kjunh972/loex/internal/process"
-	"github.com/kjunh972/loex/pkg/models"
 )
 
 var (
 	serviceFlag string
+	noDepsFlag  bool
 )
 
 var startCmd = &cobra.Command{
 	Use:   "start [project] [service]",
 	Short: "Start services for a project",
-	Long:  `Start all services (frontend, backend, database) for the specified project, or start a specific service by providing the service name.`,
-	Args:  cobra.RangeArgs(1, 2),
+	Long:  `Start all configured services for the specified project, or start a specific service by providing the service name.
+Without a project, the project declared in the nearest loex.yaml is started.`,
+	Args:  cobra.RangeArgs(0, 2),
 	Run: func(cmd *cobra.Command, args []string) {
-		projectName := args[0]
+		projectName := projectArg(args)
 		
 		configManager, err := config.NewManager()
 		if err != nil {
@@ -37,6 +38,7 @@ var startCmd = &cobra.Command{
 
 		loggerManager := logger.NewManager(configManager)
 		processManager := process.NewManager(configManager, loggerManager)
+		processManager.SetPortConflictHandler(offerToStopPortOwner(processManager))
 
 		project, err := configManager.LoadProject(projectName)
 		if err != nil {
@@ -53,7 +55,6 @@ var startCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		var servicesToStart []models.ServiceType
 		var specificService string
 
 		if len(args) == 2 {
@@ -62,31 +63,40 @@ var startCmd = &cobra.Command{
 			specificService = serviceFlag
 		}
 
+		servicesToStart := project.ServiceNames()
+		skipRunning := make(map[string]bool)
 		if specificService != "" {
-			serviceType := models.ServiceType(specificService)
-			if _, exists := project.Services[serviceType]; !exists {
+			if _, exists := project.Services[specificService]; !exists {
 				fmt.Printf("Service '%s' not configured for project '%s'\n", specificService, projectName)
 				os.Exit(1)
 			}
-			servicesToStart = []models.ServiceType{serviceType}
-		} else {
-			serviceOrder := []models.ServiceType{models.ServiceDB, models.ServiceBackend, models.ServiceFrontend}
-			for _, serviceType := range serviceOrder {
-				if _, exists := project.Services[serviceType]; exists {
-					servicesToStart = append(servicesToStart, serviceType)
+			servicesToStart = []string{specificService}
+			if !noDepsFlag {
+				servicesToStart, err = project.WithDependencies(servicesToStart)
+				if err != nil {
+					fmt.Printf("Error: %v\n", err)
+					os.Exit(1)
+				}
+				for _, serviceName := range servicesToStart {
+					skipRunning[serviceName] = serviceName != specificService
 				}
 			}
+		} else {
+			for _, serviceName := range servicesToStart {
+				skipRunning[serviceName] = true
+			}
+		}
+
+		failed, err := processManager.StartServices(project, servicesToStart, skipRunning)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
 		}
 
 		var errors []string
-		for i, serviceType := range servicesToStart {
-			if err := processManager.StartService(projectName, serviceType); err != nil {
-				errors = append(errors, fmt.Sprintf("%s: %v", serviceType, err))
-			} else {
-				if i < len(servicesToStart)-1 {
-					fmt.Printf("Waiting for %s to start...\n", serviceType)
-					time.Sleep(3 * time.Second)
-				}
+		for _, serviceName := range servicesToStart {
+			if err, ok := failed[serviceName]; ok {
+				errors = append(errors, fmt.Sprintf("%s: %v", serviceName, err))
 			}
 		}
 
@@ -109,6 +119,32 @@ var startCmd = &cobra.Command{
 	},
 }
 
+// offerToStopPortOwner returns a port conflict handler that offers to stop
+// the loex service holding the port. Services start in parallel, so the
+// questions are asked one at a time.
+func offerToStopPortOwner(processManager *process.Manager) func(conflict *process.PortConflictError) bool {
+	var mu sync.Mutex
+	return func(conflict *process.PortConflictError) bool {
+		owner := conflict.LoexOwner()
+		if owner == nil || !isTerminal(os.Stdin) {
+			return false
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		question := fmt.Sprintf("Port %d of %s is in use by %s. Stop it? (y/N): ", conflict.Port, conflict.Service, owner)
+		if !confirm(question, false) {
+			return false
+		}
+		if err := processManager.StopService(owner.Project, owner.Service); err != nil {
+			fmt.Printf("Failed to stop %s: %v\n", owner.Service, err)
+			return false
+		}
+		return true
+	}
+}
+
 func init() {
-	startCmd.Flags().StringVarP(&serviceFlag, "service", "s", "", "Start specific service (frontend, backend, db)")
+	startCmd.Flags().StringVarP(&serviceFlag, "service", "s", "", "Start specific service by name")
+	startCmd.Flags().BoolVar(&noDepsFlag, "no-deps", false, "Do not start the dependencies of the given service")
 }
\ No newline at end of file
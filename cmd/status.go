@@ -3,39 +3,51 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/kjunh972/loex/internal/config"
 	"github.com/kjunh972/loex/internal/logger"
 	"github.com/kjunh972/loex/internal/process"
+	"github.com/kjunh972/loex/pkg/models"
 )
 
 var statusCmd = &cobra.Command{
 	Use:   "status [project]",
 	Short: "Check status of project services",
-	Long:  `Display the current status of all services for the specified project.`,
-	Args:  cobra.ExactArgs(1),
+	Long:  `Display the current status of all services for the specified project.
+
+The exit code tells whether the project is running: 0 when all services are
+running, 3 when some are not, 4 when none are, 5 when all are running but some
+are unhealthy, and 1 on errors.`,
+	Args:  cobra.RangeArgs(0, 1),
 	Run: func(cmd *cobra.Command, args []string) {
-		projectName := args[0]
+		projectName := projectArg(args)
 		
 		configManager, err := config.NewManager()
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
+			errorf("Error: %v\n", err)
 			os.Exit(1)
 		}
 
 		if !configManager.ProjectExists(projectName) {
-			fmt.Printf("Project '%s' not found.\n", projectName)
+			errorf("Project '%s' not found.\n", projectName)
 			os.Exit(1)
 		}
 
 		project, err := configManager.LoadProject(projectName)
 		if err != nil {
-			fmt.Printf("Failed to load project: %v\n", err)
+			errorf("Failed to load project: %v\n", err)
 			os.Exit(1)
 		}
 
 		if len(project.Services) == 0 {
+			if structuredOutput() {
+				printStructured(ProjectStatus{Project: projectName, Services: []ServiceStatus{}})
+				return
+			}
 			fmt.Printf("Project '%s' has no configured services\n", projectName)
 			return
 		}
@@ -45,44 +57,94 @@ var statusCmd = &cobra.Command{
 
 		status, err := processManager.GetAllServicesStatus(projectName)
 		if err != nil {
-			fmt.Printf("Failed to get status: %v\n", err)
+			errorf("Failed to get status: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("Status for project '%s':\n\n", projectName)
+		result := ProjectStatus{
+			Project:       projectName,
+			SupervisorPID: processManager.SupervisorPID(projectName),
+			Total:         len(project.Services),
+			Services:      []ServiceStatus{},
+		}
+		allocated := processManager.AllocatedPorts(projectName)
+		for _, serviceName := range project.ServiceNames() {
+			info, _ := processManager.GetProcessDetails(projectName, serviceName)
+			serviceStatus := newServiceStatus(serviceName, project.Services[serviceName], status[serviceName], info)
+			setPorts(&serviceStatus, project.Services[serviceName], allocated[serviceName])
+			if process.IsActive(serviceStatus.Status) {
+				result.Running++
+			}
+			result.Services = append(result.Services, serviceStatus)
+		}
+
+		if structuredOutput() {
+			printStructured(result)
+			os.Exit(statusExitCode(result.Services))
+		}
+
+		fmt.Printf("Status for project '%s':\n", projectName)
+		if result.SupervisorPID != 0 {
+			fmt.Printf("Supervisor: running (PID: %d)\n", result.SupervisorPID)
+		}
+		fmt.Println()
 		
-		for serviceType, service := range project.Services {
-			serviceStatus := status[serviceType]
-			statusIcon := getStatusIcon(serviceStatus)
+		for _, serviceStatus := range result.Services {
+			service := project.Services[serviceStatus.Name]
+			statusIcon := getStatusIcon(serviceStatus.Status)
 			
-			fmt.Printf("  %s %s\n", statusIcon, serviceType)
+			fmt.Printf("  %s %s\n", statusIcon, serviceStatus.Name)
+			if service.Kind != "" {
+				fmt.Printf("    Kind: %s\n", service.Kind)
+			}
+			if len(service.DependsOn) > 0 {
+				fmt.Printf("    Depends on: %s\n", strings.Join(service.DependsOn, ", "))
+			}
 			fmt.Printf("    Command: %s\n", service.Command)
+			if service.Shell != "" {
+				fmt.Printf("    Shell: %s\n", service.Shell)
+			}
 			fmt.Printf("    Directory: %s\n", service.Dir)
-			fmt.Printf("    Status: %s\n", serviceStatus)
+			if len(service.Ports) > 0 || len(service.NamedPorts) > 0 {
+				fmt.Printf("    Ports: %s\n", formatPortStatus(serviceStatus, service))
+			}
+			fmt.Printf("    Status: %s\n", serviceStatus.Status)
 			
-			if serviceStatus == "running" {
-				if processInfo, err := processManager.GetProcessDetails(projectName, serviceType); err == nil {
-					fmt.Printf("    PID: %d\n", processInfo.PID)
-					fmt.Printf("    Started: %s\n", processInfo.StartTime.Format("2006-01-02 15:04:05"))
-				}
+			if serviceStatus.HealthCheck != "" {
+				fmt.Printf("    Health check: %s\n", serviceStatus.HealthCheck)
 			}
-			fmt.Println()
-		}
-
-		runningCount := 0
-		for _, s := range status {
-			if s == "running" {
-				runningCount++
+			
+			if service.LogFormat != "" {
+				fmt.Printf("    Log format: %s\n", service.LogFormat)
 			}
+			if serviceStatus.Restart != "" {
+				fmt.Printf("    Restart policy: %s\n", serviceStatus.Restart)
+			}
+			
+			if serviceStatus.PID != 0 {
+				fmt.Printf("    PID: %d\n", serviceStatus.PID)
+				fmt.Printf("    Started: %s\n", serviceStatus.StartTime.Format("2006-01-02 15:04:05"))
+			}
+			if serviceStatus.NextRestart != nil {
+				fmt.Printf("    Next restart: %s\n", serviceStatus.NextRestart.Format("2006-01-02 15:04:05"))
+			}
+			if serviceStatus.Restarts > 0 {
+				fmt.Printf("    Restarts: %d\n", serviceStatus.Restarts)
+			}
+			if serviceStatus.LastExitCode != nil {
+				fmt.Printf("    Last exit code: %d\n", *serviceStatus.LastExitCode)
+			}
+			fmt.Println()
 		}
 
-		if runningCount == 0 {
+		if result.Running == 0 {
 			fmt.Printf("Use 'loex start %s' to start services\n", projectName)
-		} else if runningCount < len(status) {
+		} else if result.Running < result.Total {
 			fmt.Printf("Use 'loex start %s' to start remaining services\n", projectName)
 		} else {
 			fmt.Printf("Use 'loex stop %s' to stop services\n", projectName)
 		}
+		os.Exit(statusExitCode(result.Services))
 	},
 }
 
@@ -90,6 +152,16 @@ func getStatusIcon(status string) string {
 	switch status {
 	case "running":
 		return "[RUNNING]"
+	case "starting":
+		return "[STARTING]"
+	case "ready":
+		return "[READY]"
+	case "unhealthy":
+		return "[UNHEALTHY]"
+	case "restarting":
+		return "[RESTARTING]"
+	case "exited":
+		return "[EXITED]"
 	case "stopped":
 		return "[STOPPED]"
 	case "error":
@@ -97,4 +169,44 @@ func getStatusIcon(status string) string {
 	default:
 		return "[UNKNOWN]"
 	}
-}
\ No newline at end of file
+}
+
+// formatPortStatus lists the ports of a service with whether they are
+// listening, or in use by another process while the service is stopped.
+// Named ports are shown as NAME=PORT; auto ports not allocated yet as
+// NAME=auto.
+func formatPortStatus(s ServiceStatus, service models.Service) string {
+	var values []string
+	format := func(label string, port int) {
+		listening := slices.Contains(s.Listening, port)
+		switch {
+		case process.IsActive(s.Status) && listening:
+			label += " (listening)"
+		case process.IsActive(s.Status):
+			label += " (not listening)"
+		case listening:
+			label += " (in use)"
+		}
+		values = append(values, label)
+	}
+
+	for _, port := range s.Ports {
+		format(strconv.Itoa(port), port)
+	}
+	for _, name := range sortedKeys(service.NamedPorts) {
+		if port, ok := s.NamedPorts[name]; ok {
+			format(fmt.Sprintf("%s=%d", name, port), port)
+		} else {
+			values = append(values, name+"="+string(service.NamedPorts[name]))
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+func formatPorts(ports []int) string {
+	values := make([]string, len(ports))
+	for i, port := range ports {
+		values[i] = strconv.Itoa(port)
+	}
+	return strings.Join(values, ", ")
+}
@@ -8,16 +8,22 @@ import (
 	"github.com/kjunh972/loex/internal/config"
 	"github.com/kjunh972/loex/internal/logger"
 	"github.com/kjunh972/loex/internal/process"
-	"github.com/kjunh972/loex/pkg/models"
 )
 
+var forceStopFlag bool
+
 var stopCmd = &cobra.Command{
-	Use:   "stop [project]",
+	Use:   "stop [project] [service]",
 	Short: "Stop services for a project",
-	Long:  `Stop all running services for the specified project, or stop a specific service with --service flag.`,
-	Args:  cobra.ExactArgs(1),
+	Long:  `Stop all running services for the specified project, or stop a specific service by providing the service name.
+
+Each service is sent its stop signal (SIGTERM unless configured with
+--stop-signal) and killed with SIGKILL if it has not exited after its stop
+timeout (10s unless configured with --stop-timeout). --force sends SIGKILL
+right away.`,
+	Args:  cobra.RangeArgs(0, 2),
 	Run: func(cmd *cobra.Command, args []string) {
-		projectName := args[0]
+		projectName := projectArg(args)
 		
 		configManager, err := config.NewManager()
 		if err != nil {
@@ -32,14 +38,19 @@ var stopCmd = &cobra.Command{
 
 		loggerManager := logger.NewManager(configManager)
 		processManager := process.NewManager(configManager, loggerManager)
+		processManager.SetForceStop(forceStopFlag)
+
+		specificService := serviceFlag
+		if len(args) == 2 {
+			specificService = args[1]
+		}
 
-		if serviceFlag != "" {
-			serviceType := models.ServiceType(serviceFlag)
-			if err := processManager.StopService(projectName, serviceType); err != nil {
-				fmt.Printf("Failed to stop service '%s': %v\n", serviceFlag, err)
+		if specificService != "" {
+			if err := processManager.StopService(projectName, specificService); err != nil {
+				fmt.Printf("Failed to stop service '%s': %v\n", specificService, err)
 				os.Exit(1)
 			}
-			fmt.Printf("Service '%s' stopped for project '%s'\n", serviceFlag, projectName)
+			fmt.Printf("Service '%s' stopped for project '%s'\n", specificService, projectName)
 		} else {
 			if err := processManager.StopAllServices(projectName); err != nil {
 				fmt.Printf("Failed to stop services: %v\n", err)
@@ -51,5 +62,6 @@ var stopCmd = &cobra.Command{
 }
 
 func init() {
-	stopCmd.Flags().StringVarP(&serviceFlag, "service", "s", "", "Stop specific service (frontend, backend, db)")
+	stopCmd.Flags().StringVarP(&serviceFlag, "service", "s", "", "Stop specific service by name")
+	stopCmd.Flags().BoolVarP(&forceStopFlag, "force", "f", false, "Kill services with SIGKILL without waiting for them to exit")
 }
\ No newline at end of file
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/kjunh972/loex/internal/config"
+	"github.com/kjunh972/loex/internal/logger"
+	"github.com/kjunh972/loex/internal/process"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var upColors = []string{"\033[36m", "\033[33m", "\033[32m", "\033[35m", "\033[34m", "\033[91m", "\033[96m", "\033[93m"}
+
+const colorReset = "\033[0m"
+
+var upCmd = &cobra.Command{
+	Use:   "up [project] [service...]",
+	Short: "Run services in the foreground with combined output",
+	Long: `Start services in the foreground and stream their output to the terminal, each
+line prefixed with the service name. Output is also written to the service log
+files. Press Ctrl-C to stop all services in reverse dependency order.`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectName := projectArg(args)
+
+		configManager, err := config.NewManager()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !configManager.ProjectExists(projectName) {
+			fmt.Printf("Project '%s' not found. Use 'loex init %s' first.\n", projectName, projectName)
+			os.Exit(1)
+		}
+
+		project, err := configManager.LoadProject(projectName)
+		if err != nil {
+			fmt.Printf("Failed to load project: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(project.Services) == 0 {
+			fmt.Printf("No services configured for project '%s'\n", projectName)
+			os.Exit(1)
+		}
+
+		servicesToStart := project.ServiceNames()
+		if len(args) > 1 {
+			servicesToStart = args[1:]
+			if !noDepsFlag {
+				servicesToStart, err = project.WithDependencies(servicesToStart)
+				if err != nil {
+					fmt.Printf("Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+		}
+
+		loggerManager := logger.NewManager(configManager)
+		processManager := process.NewManager(configManager, loggerManager)
+		processManager.SetPortConflictHandler(offerToStopPortOwner(processManager))
+
+		width := 0
+		for _, serviceName := range servicesToStart {
+			if len(serviceName) > width {
+				width = len(serviceName)
+			}
+		}
+		useColor := isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+
+		var outputMu sync.Mutex
+		var writersMu sync.Mutex
+		writers := make(map[string]*logger.PrefixWriter)
+		processManager.SetOutput(func(serviceName string) io.Writer {
+			prefix := fmt.Sprintf("[%-*s] ", width, serviceName)
+			if useColor {
+				color := upColors[indexOf(servicesToStart, serviceName)%len(upColors)]
+				prefix = color + prefix + colorReset
+			}
+			writer := logger.NewPrefixWriter(os.Stdout, prefix, &outputMu)
+			writersMu.Lock()
+			writers[serviceName] = writer
+			writersMu.Unlock()
+			return writer
+		})
+
+		var children sync.WaitGroup
+		var ownedMu sync.Mutex
+		owned := make(map[string]bool)
+		allExited := make(chan struct{})
+		processManager.SetLaunchHook(func(serviceName string, child *exec.Cmd) {
+			ownedMu.Lock()
+			owned[serviceName] = true
+			ownedMu.Unlock()
+
+			children.Add(1)
+			go func() {
+				defer children.Done()
+				child.Wait()
+
+				writersMu.Lock()
+				if writer := writers[serviceName]; writer != nil {
+					writer.Flush()
+				}
+				writersMu.Unlock()
+
+				outputMu.Lock()
+				fmt.Printf("Service '%s' exited with code %d\n", serviceName, child.ProcessState.ExitCode())
+				outputMu.Unlock()
+			}()
+		})
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+		skipRunning := make(map[string]bool)
+		for _, serviceName := range servicesToStart {
+			skipRunning[serviceName] = true
+		}
+
+		started := make(chan map[string]error, 1)
+		go func() {
+			failed, err := processManager.StartServices(project, servicesToStart, skipRunning)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			started <- failed
+		}()
+
+		exitCode := 0
+		select {
+		case sig := <-signals:
+			fmt.Printf("\nReceived %s, stopping services...\n", sig)
+			processManager.CancelStarts()
+			<-started
+		case failed := <-started:
+			for serviceName, err := range failed {
+				fmt.Printf("Service '%s' failed to start: %v\n", serviceName, err)
+				exitCode = 1
+			}
+
+			go func() {
+				children.Wait()
+				close(allExited)
+			}()
+
+			select {
+			case sig := <-signals:
+				fmt.Printf("\nReceived %s, stopping services...\n", sig)
+			case <-allExited:
+				fmt.Printf("All services exited\n")
+			}
+		}
+
+		levels, err := project.StopLevels(servicesToStart)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, level := range levels {
+			for _, serviceName := range level {
+				ownedMu.Lock()
+				isOwned := owned[serviceName]
+				ownedMu.Unlock()
+				if !isOwned {
+					continue
+				}
+				if isRunning, _ := processManager.IsServiceRunning(projectName, serviceName); isRunning {
+					if err := processManager.StopService(projectName, serviceName); err != nil {
+						fmt.Printf("Failed to stop %s service: %v\n", serviceName, err)
+						exitCode = 1
+					}
+				}
+			}
+		}
+
+		signal.Stop(signals)
+		children.Wait()
+		os.Exit(exitCode)
+	},
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return 0
+}
+
+// isTerminal reports whether file is a terminal. Other character devices
+// such as /dev/null are not.
+func isTerminal(file *os.File) bool {
+	return term.IsTerminal(int(file.Fd()))
+}
+
+func init() {
+	upCmd.Flags().BoolVar(&noDepsFlag, "no-deps", false, "Do not start the dependencies of the given services")
+}
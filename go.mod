@@ -5,9 +5,12 @@ go 1.24.5
 require (
 	github.com/hashicorp/go-version v1.6.0
 	github.com/spf13/cobra v1.9.1
+	golang.org/x/term v0.34.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.6 // indirect
+	golang.org/x/sys v0.35.0 // indirect
 )
@@ -15,6 +15,8 @@ const (
 	ProjectsDir   = "projects"
 	PIDsDir       = "pids"
 	LogsDir       = "logs"
+	SettingsFile  = "config.json"
+	DetectorsDir  = "detectors.d"
 )
 
 type Manager struct {
@@ -42,6 +44,7 @@ func ensureDirectories(basePath string) error {
 		filepath.Join(basePath, ProjectsDir),
 		filepath.Join(basePath, PIDsDir),
 		filepath.Join(basePath, LogsDir),
+		filepath.Join(basePath, LocksDir),
 	}
 
 	for _, dir := range dirs {
@@ -64,7 +67,116 @@ func (m *Manager) GetLogsPath(name string) string {
 	return filepath.Join(m.configPath, LogsDir, name)
 }
 
+func (m *Manager) GetSettingsPath() string {
+	return filepath.Join(m.configPath, SettingsFile)
+}
+
+// GetDetectorsPath returns the directory of the user detection rules.
+func (m *Manager) GetDetectorsPath() string {
+	return filepath.Join(m.configPath, DetectorsDir)
+}
+
+func (m *Manager) LoadSettings() (*models.Settings, error) {
+	data, err := os.ReadFile(m.GetSettingsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &models.Settings{}, nil
+		}
+		return nil, fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	var settings models.Settings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+func (m *Manager) SaveSettings(settings *models.Settings) error {
+	unlock, err := m.lock(m.GetSettingsPath())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return m.writeSettings(settings)
+}
+
+// UpdateSettings applies update to the settings while holding their lock.
+func (m *Manager) UpdateSettings(update func(settings *models.Settings) error) error {
+	unlock, err := m.lock(m.GetSettingsPath())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	settings, err := m.LoadSettings()
+	if err != nil {
+		return err
+	}
+	if err := update(settings); err != nil {
+		return err
+	}
+	return m.writeSettings(settings)
+}
+
+func (m *Manager) writeSettings(settings *models.Settings) error {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal settings: %w", err)
+	}
+
+	return writeFileAtomic(m.GetSettingsPath(), data, 0644)
+}
+
 func (m *Manager) SaveProject(project *models.Project) error {
+	unlock, err := m.lock(m.GetProjectPath(project.Name))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return m.writeProject(project)
+}
+
+// UpdateProject applies update to a project while holding its lock, so
+// changes made concurrently by other loex processes are not lost. A
+// project that does not exist yet is created.
+func (m *Manager) UpdateProject(name string, update func(project *models.Project) error) error {
+	unlock, err := m.lock(m.GetProjectPath(name))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var project *models.Project
+	if m.ProjectExists(name) {
+		if project, err = m.readProjectFile(name); err != nil {
+			return err
+		}
+	} else {
+		project = &models.Project{
+			Name:     name,
+			Services: make(map[string]models.Service),
+			Created:  time.Now(),
+		}
+	}
+	if project.Services == nil {
+		project.Services = make(map[string]models.Service)
+	}
+
+	if err := update(project); err != nil {
+		return err
+	}
+	return m.writeProject(project)
+}
+
+func (m *Manager) writeProject(project *models.Project) error {
+	if project.Manifest != "" {
+		return fmt.Errorf("project '%s' is defined in %s; edit the manifest instead", project.Name, project.Manifest)
+	}
+
 	project.Updated = time.Now()
 	
 	data, err := json.MarshalIndent(project, "", "  ")
@@ -72,11 +184,31 @@ func (m *Manager) SaveProject(project *models.Project) error {
 		return fmt.Errorf("failed to marshal project: %w", err)
 	}
 
-	projectPath := m.GetProjectPath(project.Name)
-	return os.WriteFile(projectPath, data, 0644)
+	return writeFileAtomic(m.GetProjectPath(project.Name), data, 0644)
 }
 
+// LoadProject loads a project from the registry. Projects registered from
+// a manifest are read from the manifest.
 func (m *Manager) LoadProject(name string) (*models.Project, error) {
+	project, err := m.readProjectFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if project.Manifest != "" {
+		created := project.Created
+		project, err = LoadManifest(project.Manifest)
+		if err != nil {
+			return nil, err
+		}
+		project.Name = name
+		project.Created = created
+	}
+
+	return project, nil
+}
+
+func (m *Manager) readProjectFile(name string) (*models.Project, error) {
 	projectPath := m.GetProjectPath(name)
 	
 	data, err := os.ReadFile(projectPath)
@@ -119,11 +251,19 @@ func (m *Manager) ProjectExists(name string) bool {
 	return err == nil
 }
 
+// DeleteProject removes a project with its PID file and logs. It holds the
+// locks of both files, so a concurrent update cannot bring them back.
 func (m *Manager) DeleteProject(name string) error {
 	projectPath := m.GetProjectPath(name)
 	pidPath := m.GetPIDPath(name)
 	logsPath := m.GetLogsPath(name)
 
+	unlock, err := m.lockAll(projectPath, pidPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	if err := os.Remove(projectPath); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to delete project file: %w", err)
 	}
@@ -139,7 +279,15 @@ func (m *Manager) DeleteProject(name string) error {
 	return nil
 }
 
+// RenameProject moves a project with its PID file and logs to a new name,
+// holding the locks of the old and new files throughout.
 func (m *Manager) RenameProject(oldName, newName string) error {
+	unlock, err := m.lockAll(m.GetProjectPath(oldName), m.GetPIDPath(oldName), m.GetProjectPath(newName), m.GetPIDPath(newName))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	if !m.ProjectExists(oldName) {
 		return fmt.Errorf("project '%s' does not exist", oldName)
 	}
@@ -148,13 +296,16 @@ func (m *Manager) RenameProject(oldName, newName string) error {
 		return fmt.Errorf("project '%s' already exists", newName)
 	}
 
-	project, err := m.LoadProject(oldName)
+	project, err := m.readProjectFile(oldName)
 	if err != nil {
 		return err
 	}
+	if project.Manifest != "" {
+		return fmt.Errorf("project '%s' is defined in %s; change its name there instead", oldName, project.Manifest)
+	}
 
 	project.Name = newName
-	if err := m.SaveProject(project); err != nil {
+	if err := m.writeProject(project); err != nil {
 		return err
 	}
 
@@ -178,6 +329,40 @@ func (m *Manager) RenameProject(oldName, newName string) error {
 }
 
 func (m *Manager) SaveProjectPIDs(pids *models.ProjectPIDs) error {
+	unlock, err := m.lock(m.GetPIDPath(pids.ProjectName))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return m.writeProjectPIDs(pids)
+}
+
+// UpdateProjectPIDs applies update to the PID file of a project while
+// holding its lock, so concurrent starts and stops do not drop each
+// other's entries.
+func (m *Manager) UpdateProjectPIDs(name string, update func(pids *models.ProjectPIDs) error) error {
+	unlock, err := m.lock(m.GetPIDPath(name))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	pids, err := m.LoadProjectPIDs(name)
+	if err != nil {
+		return err
+	}
+	if pids.Services == nil {
+		pids.Services = make(map[string]models.ProcessInfo)
+	}
+
+	if err := update(pids); err != nil {
+		return err
+	}
+	return m.writeProjectPIDs(pids)
+}
+
+func (m *Manager) writeProjectPIDs(pids *models.ProjectPIDs) error {
 	pids.Updated = time.Now()
 	
 	data, err := json.MarshalIndent(pids, "", "  ")
@@ -185,8 +370,7 @@ func (m *Manager) SaveProjectPIDs(pids *models.ProjectPIDs) error {
 		return fmt.Errorf("failed to marshal PIDs: %w", err)
 	}
 
-	pidPath := m.GetPIDPath(pids.ProjectName)
-	return os.WriteFile(pidPath, data, 0644)
+	return writeFileAtomic(m.GetPIDPath(pids.ProjectName), data, 0644)
 }
 
 func (m *Manager) LoadProjectPIDs(name string) (*models.ProjectPIDs, error) {
@@ -197,7 +381,7 @@ func (m *Manager) LoadProjectPIDs(name string) (*models.ProjectPIDs, error) {
 		if os.IsNotExist(err) {
 			return &models.ProjectPIDs{
 				ProjectName: name,
-				Services:    make(map[models.ServiceType]models.ProcessInfo),
+				Services:    make(map[string]models.ProcessInfo),
 			}, nil
 		}
 		return nil, fmt.Errorf("failed to read PID file: %w", err)
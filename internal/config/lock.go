@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+)
+
+// LocksDir holds the lock files of the state files under the config
+// directory. Locks live apart from the files they guard because writes
+// replace those files.
+const LocksDir = "locks"
+
+// lockPath returns the lock file of a state file. It is named after the
+// path of the state file under the config directory, e.g.
+// projects-foo.json.lock, so files with the same name in different
+// directories do not share a lock.
+func (m *Manager) lockPath(path string) string {
+	name := filepath.Base(path)
+	if rel, err := filepath.Rel(m.configPath, path); err == nil {
+		name = strings.ReplaceAll(filepath.ToSlash(rel), "/", "-")
+	}
+	return filepath.Join(m.configPath, LocksDir, name+".lock")
+}
+
+// lock takes an exclusive advisory lock for a state file and returns the
+// function releasing it. The lock is held across a load-modify-save cycle
+// so concurrent loex processes do not overwrite each other's changes.
+func (m *Manager) lock(path string) (func(), error) {
+	file, err := os.OpenFile(m.lockPath(path), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	for {
+		err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+		if err != syscall.EINTR {
+			break
+		}
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	return func() {
+		syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
+
+// lockAll takes the locks of several state files and returns the function
+// releasing them. The locks are always taken in the same order, so callers
+// locking overlapping files cannot deadlock.
+func (m *Manager) lockAll(paths ...string) (func(), error) {
+	paths = slices.Clone(paths)
+	slices.SortFunc(paths, func(a, b string) int {
+		return strings.Compare(m.lockPath(a), m.lockPath(b))
+	})
+	paths = slices.CompactFunc(paths, func(a, b string) bool {
+		return m.lockPath(a) == m.lockPath(b)
+	})
+
+	var unlocks []func()
+	unlockAll := func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+	for _, path := range paths {
+		unlock, err := m.lock(path)
+		if err != nil {
+			unlockAll()
+			return nil, err
+		}
+		unlocks = append(unlocks, unlock)
+	}
+	return unlockAll, nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it over path, so readers never see a partially written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
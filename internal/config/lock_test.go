@@ -0,0 +1,140 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+func TestUpdateProjectPIDsConcurrent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const writers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := manager.UpdateProjectPIDs("shop", func(pids *models.ProjectPIDs) error {
+				pids.Services[fmt.Sprintf("svc%d", i)] = models.ProcessInfo{PID: 1000 + i}
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	pids, err := manager.LoadProjectPIDs("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pids.Services) != writers {
+		t.Fatalf("Expected %d services, got %d", writers, len(pids.Services))
+	}
+
+	entries, err := os.ReadDir(filepath.Join(manager.configPath, PIDsDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected only the PID file, got %d entries", len(entries))
+	}
+}
+
+func TestUpdateProjectCreatesAndKeepsServices(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"api", "web"} {
+		err := manager.UpdateProject("shop", func(project *models.Project) error {
+			project.Services[name] = models.Service{Command: "run " + name}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	project, err := manager.LoadProject("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(project.Services) != 2 || project.Services["web"].Command != "run web" {
+		t.Fatalf("Unexpected services: %+v", project.Services)
+	}
+}
+
+func TestLockPathsAreDistinct(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Project foo-pids and the PID file of project foo share a file name,
+	// as do project config and the settings.
+	paths := []string{
+		manager.GetProjectPath("foo-pids"),
+		manager.GetPIDPath("foo"),
+		manager.GetProjectPath("config"),
+		manager.GetSettingsPath(),
+	}
+	locks := make(map[string]string)
+	for _, path := range paths {
+		lockPath := manager.lockPath(path)
+		if other, exists := locks[lockPath]; exists {
+			t.Errorf("%s and %s share the lock %s", other, path, lockPath)
+		}
+		locks[lockPath] = path
+	}
+	if lockPath := manager.lockPath(manager.GetPIDPath("foo")); filepath.Base(lockPath) != "pids-foo-pids.json.lock" {
+		t.Errorf("Unexpected lock file %s", lockPath)
+	}
+}
+
+func TestRenameProjectConcurrentUpdates(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SaveProject(&models.Project{Name: "shop", Services: map[string]models.Service{}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rename holds the PID file lock while updates of the PID file
+	// wait for it.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := manager.RenameProject("shop", "store"); err != nil {
+			t.Error(err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		manager.UpdateProjectPIDs("shop", func(pids *models.ProjectPIDs) error {
+			pids.Services["api"] = models.ProcessInfo{PID: 1000}
+			return nil
+		})
+	}()
+	wg.Wait()
+
+	if manager.ProjectExists("shop") || !manager.ProjectExists("store") {
+		t.Fatal("Expected the project to be renamed")
+	}
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kjunh972/loex/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestNames are the file names of a repository-local project manifest,
+// in order of preference.
+var ManifestNames = []string{"loex.yaml", "loex.yml", "loex.json"}
+
+// FindManifest walks up from dir and returns the path of the first project
+// manifest found.
+func FindManifest(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		for _, name := range ManifestNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no %s found in this directory or any parent", ManifestNames[0])
+		}
+		dir = parent
+	}
+}
+
+// LoadManifest reads a project manifest. It has the same fields as a
+// project file, but service directories are relative to the manifest and
+// default to its directory. The project name defaults to the name of that
+// directory.
+func LoadManifest(path string) (*models.Project, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	if !strings.HasSuffix(path, ".json") {
+		// Decode YAML generically and reuse the JSON mapping of the models.
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+		}
+	}
+
+	var project models.Project
+	if err := json.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	root := filepath.Dir(path)
+	if project.Name == "" {
+		project.Name = filepath.Base(root)
+	}
+	if project.Services == nil {
+		project.Services = make(map[string]models.Service)
+	}
+	for name, service := range project.Services {
+		if !filepath.IsAbs(service.Dir) {
+			service.Dir = filepath.Join(root, service.Dir)
+		}
+		project.Services[name] = service
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		project.Updated = info.ModTime()
+	}
+	project.Manifest = path
+	return &project, nil
+}
+
+// RegisterManifest loads a manifest and registers it under its project
+// name, so it can be used by name like any other project. The registry
+// only refers to the manifest; the manifest stays the source of truth.
+func (m *Manager) RegisterManifest(path string) (*models.Project, error) {
+	project, err := LoadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := m.lock(m.GetProjectPath(project.Name))
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if m.ProjectExists(project.Name) {
+		existing, err := m.readProjectFile(project.Name)
+		if err != nil {
+			return nil, err
+		}
+		if existing.Manifest == "" {
+			return nil, fmt.Errorf("project '%s' already exists and is not defined by %s", project.Name, project.Manifest)
+		}
+		if existing.Manifest == project.Manifest {
+			return project, nil
+		}
+		if _, err := os.Stat(existing.Manifest); err == nil {
+			return nil, fmt.Errorf("project '%s' is already defined by %s", project.Name, existing.Manifest)
+		}
+	}
+
+	link := models.Project{Name: project.Name, Manifest: project.Manifest, Created: project.Updated, Updated: project.Updated}
+	data, err := json.MarshalIndent(link, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal project: %w", err)
+	}
+	if err := writeFileAtomic(m.GetProjectPath(project.Name), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to register manifest: %w", err)
+	}
+
+	return project, nil
+}
+
+// DiscoverProject registers the manifest found by walking up from dir and
+// returns its project name.
+func (m *Manager) DiscoverProject(dir string) (string, error) {
+	path, err := FindManifest(dir)
+	if err != nil {
+		return "", err
+	}
+
+	project, err := m.RegisterManifest(path)
+	if err != nil {
+		return "", err
+	}
+	return project.Name, nil
+}
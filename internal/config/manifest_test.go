@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testManifest = `name: shop
+env:
+  APP_ENV: development
+services:
+  db:
+    command: docker compose up postgres
+    healthcheck:
+      tcp: "5432"
+      timeout: 2s
+  api:
+    command: go run .
+    dir: backend
+    depends_on: [db]
+`
+
+func TestFindAndLoadManifest(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "backend", "internal")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "loex.yaml"), []byte(testManifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := FindManifest(nested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(root, "loex.yaml") {
+		t.Fatalf("Expected manifest at root, got %s", path)
+	}
+
+	project, err := LoadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.Name != "shop" || project.Manifest != path || project.Env["APP_ENV"] != "development" {
+		t.Errorf("Unexpected project: %+v", project)
+	}
+	if dir := project.Services["api"].Dir; dir != filepath.Join(root, "backend") {
+		t.Errorf("Expected api dir relative to manifest, got %s", dir)
+	}
+	if dir := project.Services["db"].Dir; dir != root {
+		t.Errorf("Expected db dir to default to the manifest directory, got %s", dir)
+	}
+	if hc := project.Services["db"].Healthcheck; hc == nil || hc.TCP != "5432" || time.Duration(hc.Timeout) != 2*time.Second {
+		t.Errorf("Unexpected healthcheck: %+v", hc)
+	}
+}
+
+func TestRegisterManifest(t *testing.T) {
+	root := t.TempDir()
+	manager := &Manager{configPath: t.TempDir()}
+	if err := ensureDirectories(manager.configPath); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(root, "loex.yaml"), []byte(testManifest), 0644)
+
+	name, err := manager.DiscoverProject(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := manager.LoadProject(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(project.Services) != 2 || project.Manifest == "" {
+		t.Errorf("Expected the project to be loaded from the manifest, got %+v", project)
+	}
+	if err := manager.SaveProject(project); err == nil {
+		t.Error("Expected saving a manifest project to fail")
+	}
+}
@@ -0,0 +1,129 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+func backendResult(command, reason string) *DetectionResult {
+	return &DetectionResult{
+		Name:            string(models.ServiceBackend),
+		Kind:            models.ServiceBackend,
+		Command:         command,
+		DetectionReason: reason,
+	}
+}
+
+// gradleApplicationPlugin matches the declarations of the application
+// plugin: id 'application', id("application"), apply plugin: 'application'
+// and the bare application of a Kotlin plugins block.
+var gradleApplicationPlugin = regexp.MustCompile(`(?m)\bid\s*\(?\s*["']application["']|\bapply\s*\(?\s*plugin\s*[:=]\s*["']application["']|^\s*application\s*$`)
+
+// detectGradle runs the bootRun task of Spring Boot projects or the run
+// task of the application plugin. Other builds, such as libraries, are not
+// runnable and propose nothing.
+func detectGradle(dir string) *DetectionResult {
+	if !fileExists(filepath.Join(dir, "build.gradle")) && !fileExists(filepath.Join(dir, "build.gradle.kts")) {
+		return nil
+	}
+
+	task := gradleTask(dir)
+	if task == "" {
+		return nil
+	}
+	reason := "Detected Gradle project"
+	if fileExists(filepath.Join(dir, "gradlew")) {
+		reason += " with wrapper"
+	}
+	return backendResult(fmt.Sprintf("%s %s", gradleCommand(dir), task), reason)
+}
+
+// gradleTask returns the task running the Gradle project in dir, or "" for
+// projects without the Spring Boot or application plugin.
+func gradleTask(dir string) string {
+	var build []byte
+	for _, name := range []string{"build.gradle", "build.gradle.kts"} {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			build = data
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(string(build), "org.springframework.boot"):
+		return "bootRun"
+	case gradleApplicationPlugin.Match(build):
+		return "run"
+	}
+	return ""
+}
+
+// gradleCommand returns the wrapper of the Gradle build in dir, or the
+// global gradle without one.
+func gradleCommand(dir string) string {
+	if fileExists(filepath.Join(dir, "gradlew")) {
+		return "./gradlew"
+	}
+	return "gradle"
+}
+
+// detectDotNet runs the project file of the directory. With several, the
+// web project is chosen and passed explicitly.
+func detectDotNet(dir string) *DetectionResult {
+	projects, _ := filepath.Glob(filepath.Join(dir, "*.csproj"))
+	if len(projects) == 0 {
+		return nil
+	}
+	if len(projects) == 1 {
+		return backendResult("dotnet run", "Detected .NET project")
+	}
+
+	project := projects[0]
+	for _, candidate := range projects {
+		if data, err := os.ReadFile(candidate); err == nil && strings.Contains(string(data), "Microsoft.NET.Sdk.Web") {
+			project = candidate
+			break
+		}
+	}
+	return backendResult(fmt.Sprintf("dotnet run --project %s", filepath.Base(project)), "Detected .NET project")
+}
+
+// detectDeno runs the dev or start task of deno.json, or the main module
+// of projects without tasks.
+func detectDeno(dir string) *DetectionResult {
+	var config []byte
+	var configName string
+	for _, name := range []string{"deno.json", "deno.jsonc"} {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			config, configName = data, name
+			break
+		}
+	}
+	if config == nil {
+		return nil
+	}
+
+	var denoJSON struct {
+		Tasks map[string]interface{} `json:"tasks"`
+	}
+	if json.Unmarshal(config, &denoJSON) == nil {
+		for _, task := range []string{"dev", "start"} {
+			if _, ok := denoJSON.Tasks[task]; ok {
+				return backendResult("deno task "+task, fmt.Sprintf("Detected Deno project with %s task in %s", task, configName))
+			}
+		}
+	}
+
+	for _, main := range []string{"main.ts", "main.js", "server.ts", "mod.ts"} {
+		if fileExists(filepath.Join(dir, main)) {
+			return backendResult("deno run -A "+main, "Detected Deno project")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+func TestDetectBackendFrameworks(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		command string
+		reason  string
+	}{
+		{"maven wrapper", map[string]string{"pom.xml": "<project/>", "mvnw": "#!/bin/sh\n"},
+			"./mvnw spring-boot:run", "Detected Maven project with wrapper"},
+		{"global maven", map[string]string{"pom.xml": "<project/>"},
+			"mvn spring-boot:run", "Detected Maven project"},
+		{"gradle wrapper", map[string]string{"build.gradle.kts": "plugins { id(\"org.springframework.boot\") }\n", "gradlew": "#!/bin/sh\n"},
+			"./gradlew bootRun", "Detected Gradle project with wrapper"},
+		{"global gradle application", map[string]string{"build.gradle": "plugins { id 'application' }\n"},
+			"gradle run", "Detected Gradle project"},
+		{"gradle apply plugin", map[string]string{"build.gradle": "apply plugin: 'application'\n"},
+			"gradle run", "Detected Gradle project"},
+		{"gradle kotlin application", map[string]string{"build.gradle.kts": "plugins {\n    application\n}\n"},
+			"gradle run", "Detected Gradle project"},
+		{"django with poetry", map[string]string{"pyproject.toml": "[tool.poetry]\nname = \"site\"\n", "manage.py": ""},
+			"poetry run python manage.py runserver", "Detected Django project, using poetry (pyproject.toml)"},
+		{"fastapi with uv", map[string]string{"pyproject.toml": "[project]\n", "uv.lock": "", "app/main.py": "from fastapi import FastAPI\n\napi = FastAPI()\n"},
+			"uv run uvicorn app.main:api --reload", "Detected FastAPI project, using uv (uv.lock)"},
+		{"fastapi", map[string]string{"requirements.txt": "fastapi\nuvicorn\n", "main.py": "app: FastAPI = FastAPI(title=\"api\")\n"},
+			"uvicorn main:app --reload", "Detected FastAPI project"},
+		{"flask with pipenv", map[string]string{"Pipfile": "[packages]\nflask = \"*\"\n", "app.py": "app = Flask(__name__)\n"},
+			"pipenv run python app.py", "Detected Python Flask project, using pipenv (Pipfile)"},
+		{"rails", map[string]string{"Gemfile": "gem \"rails\", \"~> 7.1\"\n", "bin/rails": "#!/usr/bin/env ruby\n"},
+			"bin/rails server", "Detected Rails project"},
+		{"rails without binstub", map[string]string{"Gemfile": "gem 'rails'\n"},
+			"bundle exec rails server", "Detected Rails project without bin/rails"},
+		{"laravel", map[string]string{"composer.json": "{}", "artisan": "#!/usr/bin/env php\n"},
+			"php artisan serve", "Detected Laravel project"},
+		{"phoenix", map[string]string{"mix.exs": "defp deps do\n  [{:phoenix, \"~> 1.7\"}]\nend\n"},
+			"mix phx.server", "Detected Phoenix project"},
+		{"dotnet", map[string]string{"Api.csproj": "<Project Sdk=\"Microsoft.NET.Sdk.Web\"></Project>"},
+			"dotnet run", "Detected .NET project"},
+		{"dotnet with several projects", map[string]string{"Lib.csproj": "<Project Sdk=\"Microsoft.NET.Sdk\"></Project>", "Web.csproj": "<Project Sdk=\"Microsoft.NET.Sdk.Web\"></Project>"},
+			"dotnet run --project Web.csproj", "Detected .NET project"},
+		{"deno task", map[string]string{"deno.json": `{"tasks": {"start": "deno run -A main.ts", "dev": "deno run -A --watch main.ts"}}`},
+			"deno task dev", "Detected Deno project with dev task in deno.json"},
+		{"deno main module", map[string]string{"deno.json": `{"imports": {}}`, "main.ts": ""},
+			"deno run -A main.ts", "Detected Deno project"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, test.files)
+			result := proposed(New(), dir, models.ServiceBackend)
+			if result == nil {
+				t.Fatal("Expected a result")
+			}
+			if result.Command != test.command || result.DetectionReason != test.reason {
+				t.Errorf("Expected %q (%s), got %q (%s)", test.command, test.reason, result.Command, result.DetectionReason)
+			}
+		})
+	}
+
+	// An Android app sets an applicationId and reads application.yml
+	// without the application plugin.
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"Gemfile":      "gem 'sinatra'\n",
+		"mix.exs":      "defp deps do\n  []\nend\n",
+		"build.gradle": "plugins { id 'com.android.application' }\nandroid { defaultConfig { applicationId 'com.example' } }\n// see application.yml\n",
+	})
+	if result := proposed(New(), dir, models.ServiceBackend); result != nil {
+		t.Errorf("Expected no result, got %q", result.Command)
+	}
+}
@@ -0,0 +1,189 @@
+package detector
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+var pythonProject = []string{"requirements.txt", "pyproject.toml", "Pipfile"}
+
+const railsGem = `(?m)^\s*gem\s+["']rails["']`
+
+// BuiltinRules returns the rules shipped with loex. Their confidence makes
+// the more specific rule of a kind win: meta-frameworks (90) over the
+// libraries they build on (60), so a Next.js app is not proposed as React
+// and a Nuxt app not as Vue.js; wrappers over global tools; generic rules
+// such as any Node.js project with a dev script (30) last.
+func BuiltinRules() []Rule {
+	rules := []Rule{
+		nodeRule("react-native", "React Native", "react-native start", 90, "react-native"),
+		nodeRule("nextjs", "Next.js", "next dev", 90, "next"),
+		nodeRule("nuxt", "Nuxt", "nuxt dev", 90, "nuxt"),
+		nodeRule("remix-vite", "Remix", "remix vite:dev", 90, "@remix-run/*", "vite"),
+		nodeRule("remix", "Remix", "remix dev", 85, "@remix-run/*"),
+		nodeRule("sveltekit", "SvelteKit", "vite dev", 90, "@sveltejs/kit"),
+		nodeRule("astro", "Astro", "astro dev", 90, "astro"),
+		nodeRule("angular", "Angular", "ng serve", 80, "@angular/core"),
+		nodeRule("vue-vite", "Vue.js with Vite", "vite", 70, "vue", "vite"),
+		nodeRule("vue", "Vue.js", "", 60, "vue"),
+		nodeRule("react-vite", "React with Vite", "vite", 70, "react", "vite"),
+		nodeRule("create-react-app", "React", "react-scripts start", 70, "react", "react-scripts"),
+		nodeRule("react", "React", "", 60, "react"),
+		nodeRule("vite", "Vite", "vite", 50, "vite"),
+		nodeRule("node", "", "", 30),
+
+		staticRule("go-main", models.ServiceBackend, 90, Match{Files: []string{"go.mod", "main.go"}},
+			"go run main.go", "Detected Go project with main.go"),
+		staticRule("go", models.ServiceBackend, 80, Match{Files: []string{"go.mod"}},
+			"go run .", "Detected Go project"),
+		staticRule("maven-wrapper", models.ServiceBackend, 90, Match{Files: []string{"pom.xml", "mvnw"}},
+			"./mvnw spring-boot:run", "Detected Maven project with wrapper"),
+		staticRule("maven", models.ServiceBackend, 80, Match{Files: []string{"pom.xml"}},
+			"mvn spring-boot:run", "Detected Maven project"),
+		{
+			Name:        "gradle",
+			Description: "Detected Gradle project",
+			Kind:        models.ServiceBackend,
+			Confidence:  80,
+			Match:       Match{AnyFiles: []string{"build.gradle", "build.gradle.kts"}},
+			resolve:     detectGradle,
+		},
+		{
+			Name:        "django",
+			Description: "Detected Django project",
+			Kind:        models.ServiceBackend,
+			Confidence:  90,
+			Match:       Match{AnyFiles: pythonProject, Files: []string{"manage.py"}},
+			resolve: func(dir string) *DetectionResult {
+				return pythonResult(dir, "python manage.py runserver", "Detected Django project")
+			},
+		},
+		{
+			Name:        "fastapi",
+			Description: "Detected FastAPI project",
+			Kind:        models.ServiceBackend,
+			Confidence:  85,
+			Match: Match{
+				AnyFiles: pythonProject,
+				Contents: []ContentMatch{{Files: fastAPIEntrypoints, Regex: `FastAPI\(`}},
+			},
+			resolve: func(dir string) *DetectionResult {
+				if app := findFastAPIApp(dir); app != "" {
+					return pythonResult(dir, fmt.Sprintf("uvicorn %s --reload", app), "Detected FastAPI project")
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "flask",
+			Description: "Detected Python Flask project",
+			Kind:        models.ServiceBackend,
+			Confidence:  70,
+			Match:       Match{AnyFiles: pythonProject, Files: []string{"app.py"}},
+			resolve: func(dir string) *DetectionResult {
+				return pythonResult(dir, "python app.py", "Detected Python Flask project")
+			},
+		},
+		staticRule("rails", models.ServiceBackend, 90, Match{Files: []string{"Gemfile", "bin/rails"}},
+			"bin/rails server", "Detected Rails project"),
+		staticRule("rails-bundler", models.ServiceBackend, 80, Match{Contents: []ContentMatch{{Files: []string{"Gemfile"}, Regex: railsGem}}},
+			"bundle exec rails server", "Detected Rails project without bin/rails"),
+		staticRule("laravel", models.ServiceBackend, 90, Match{Files: []string{"artisan"}},
+			"php artisan serve", "Detected Laravel project"),
+		staticRule("phoenix", models.ServiceBackend, 90, Match{Contents: []ContentMatch{{Files: []string{"mix.exs"}, Regex: `:phoenix\b`}}},
+			"mix phx.server", "Detected Phoenix project"),
+		{
+			Name:        "dotnet",
+			Description: "Detected .NET project",
+			Kind:        models.ServiceBackend,
+			Confidence:  80,
+			Match:       Match{Globs: []string{"*.csproj"}},
+			resolve:     detectDotNet,
+		},
+		{
+			Name:        "deno",
+			Description: "Detected Deno project",
+			Kind:        models.ServiceBackend,
+			Confidence:  80,
+			Match:       Match{AnyFiles: []string{"deno.json", "deno.jsonc"}},
+			resolve:     detectDeno,
+		},
+		staticRule("rust", models.ServiceBackend, 80, Match{Files: []string{"Cargo.toml"}},
+			"cargo run", "Detected Rust project"),
+		{
+			Name:        "jar",
+			Description: "Detected JAR file",
+			Kind:        models.ServiceBackend,
+			Confidence:  40,
+			Match:       Match{Globs: []string{"*.jar"}},
+			resolve: func(dir string) *DetectionResult {
+				jarFiles, _ := filepath.Glob(filepath.Join(dir, "*.jar"))
+				return backendResult(fmt.Sprintf("java -jar %s", filepath.Base(jarFiles[0])), "Detected JAR file")
+			},
+		},
+
+		staticRule("docker-compose", models.ServiceDB, 80, Match{AnyFiles: []string{"docker-compose.yml", "docker-compose.yaml"}},
+			"docker-compose up -d", "Detected docker-compose.yml"),
+		{
+			Name:        "dockerfile",
+			Description: "Detected Dockerfile",
+			Kind:        models.ServiceDB,
+			Confidence:  30,
+			Match:       Match{Files: []string{"Dockerfile"}},
+			Command:     "docker build -t local-db . && docker run -d local-db",
+			rootOnly:    true,
+		},
+		{
+			Name:        "homebrew-database",
+			Description: "MySQL or PostgreSQL installed with Homebrew (macOS)",
+			Kind:        models.ServiceDB,
+			Confidence:  60,
+			resolve: func(dir string) *DetectionResult {
+				if runtime.GOOS != "darwin" {
+					return nil
+				}
+				return detectBrewDatabaseServices()
+			},
+			rootOnly: true,
+		},
+	}
+
+	for i := range rules {
+		rules[i].Source = SourceBuiltin
+	}
+	return rules
+}
+
+func staticRule(name string, kind models.ServiceType, confidence int, match Match, command, reason string) Rule {
+	return Rule{Name: name, Description: reason, Kind: kind, Confidence: confidence, Match: match, Command: command}
+}
+
+// nodeRule matches package.json projects with the given dependencies and
+// runs their dev script, or the CLI of the framework without one.
+func nodeRule(name, label, cli string, confidence int, dependencies ...string) Rule {
+	description := fmt.Sprintf("Detected %s project", label)
+	if label == "" {
+		description = "Detected Node.js project with a dev, start or serve script"
+	}
+	return Rule{
+		Name:        name,
+		Description: description,
+		Kind:        models.ServiceFrontend,
+		Confidence:  confidence,
+		Match:       Match{Files: []string{"package.json"}, Dependencies: dependencies},
+		resolve: func(dir string) *DetectionResult {
+			packageJSON := readPackageJSON(dir)
+			if packageJSON == nil {
+				return nil
+			}
+			command, reason, ok := nodeCommand(dir, packageJSON, nodeFramework{Label: label, CLI: cli})
+			if !ok {
+				return nil
+			}
+			return &DetectionResult{Command: command, DetectionReason: reason}
+		},
+	}
+}
@@ -0,0 +1,321 @@
+package detector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kjunh972/loex/internal/process"
+	"github.com/kjunh972/loex/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// composeVariable matches $VAR and ${VAR...} references, which docker
+// compose interpolates from the environment.
+var composeVariable = regexp.MustCompile(`\$(\{[^}]*\}|[A-Za-z_][A-Za-z0-9_]*)`)
+
+// composeHandled are service keys that need no counterpart in loex because
+// docker compose applies them itself when the service is run.
+var composeHandled = map[string]bool{
+	"image": true, "build": true, "command": true, "entrypoint": true,
+	"volumes": true, "networks": true, "container_name": true, "hostname": true,
+	"working_dir": true, "user": true, "labels": true, "expose": true,
+	"restart": true, "stdin_open": true, "tty": true, "privileged": true,
+	"cap_add": true, "cap_drop": true, "ulimits": true, "shm_size": true,
+	"extra_hosts": true, "dns": true, "platform": true, "pull_policy": true,
+	"stop_signal": true, "stop_grace_period": true, "tmpfs": true, "logging": true,
+	"secrets": true, "configs": true, "init": true, "healthcheck": true,
+	"depends_on": true, "ports": true, "environment": true, "env_file": true,
+}
+
+// ComposeImport is the result of reading a docker compose file.
+type ComposeImport struct {
+	Services map[string]models.Service
+	// Warnings lists settings that could not be carried over.
+	Warnings []string
+}
+
+// ParseCompose reads a docker compose file and returns one loex service per
+// compose service. Each service runs 'docker compose up --no-deps <name>'
+// in the directory of the file; depends_on, published ports, environment,
+// env files and healthchecks are carried over.
+func ParseCompose(path string) (*ComposeImport, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compose file: %w", err)
+	}
+
+	var file struct {
+		Services map[string]map[string]interface{} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse compose file: %w", err)
+	}
+	if len(file.Services) == 0 {
+		return nil, fmt.Errorf("no services found in %s", path)
+	}
+
+	result := &ComposeImport{Services: make(map[string]models.Service)}
+	c := &composeConverter{file: filepath.Base(path), result: result}
+
+	names := make([]string, 0, len(file.Services))
+	for name := range file.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		definition := file.Services[name]
+		c.service = name
+
+		service := models.Service{
+			Command: fmt.Sprintf("docker compose -f %s up --no-deps %s", process.QuoteCommand([]string{c.file}), process.QuoteCommand([]string{name})),
+			Dir:     filepath.Dir(path),
+		}
+
+		service.DependsOn = c.dependsOn(definition["depends_on"])
+		service.Ports = c.ports(definition["ports"])
+		service.Env = c.environment(definition["environment"])
+		service.EnvFiles = c.envFiles(definition["env_file"])
+		service.Healthcheck = c.healthcheck(definition["healthcheck"])
+
+		var unsupported []string
+		for key := range definition {
+			if !composeHandled[key] && !strings.HasPrefix(key, "x-") {
+				unsupported = append(unsupported, key)
+			}
+		}
+		sort.Strings(unsupported)
+		for _, key := range unsupported {
+			c.warn("'%s' is not supported and was ignored", key)
+		}
+
+		result.Services[name] = service
+	}
+
+	return result, nil
+}
+
+type composeConverter struct {
+	file    string
+	service string
+	result  *ComposeImport
+}
+
+func (c *composeConverter) warn(format string, args ...interface{}) {
+	c.result.Warnings = append(c.result.Warnings, fmt.Sprintf("%s: %s", c.service, fmt.Sprintf(format, args...)))
+}
+
+func (c *composeConverter) dependsOn(value interface{}) []string {
+	var deps []string
+	switch v := value.(type) {
+	case []interface{}:
+		for _, dep := range v {
+			deps = append(deps, fmt.Sprint(dep))
+		}
+	case map[string]interface{}:
+		for dep := range v {
+			deps = append(deps, dep)
+		}
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if opts, ok := v[dep].(map[string]interface{}); ok && opts["condition"] == "service_completed_successfully" {
+				c.warn("depends_on %s: condition service_completed_successfully is treated as service_started", dep)
+			}
+		}
+	}
+	return deps
+}
+
+func (c *composeConverter) ports(value interface{}) []int {
+	entries, _ := value.([]interface{})
+
+	var ports []int
+	for _, entry := range entries {
+		var published, protocol string
+		switch v := entry.(type) {
+		case map[string]interface{}:
+			if v["published"] != nil {
+				published = fmt.Sprint(v["published"])
+			}
+			if v["protocol"] != nil {
+				protocol = fmt.Sprint(v["protocol"])
+			}
+		default:
+			var ok bool
+			if published, protocol, ok = shortPort(fmt.Sprint(v)); !ok {
+				c.warn("port %v: invalid port", entry)
+				continue
+			}
+		}
+
+		if published == "" {
+			// Only exposed to other containers.
+			continue
+		}
+		if protocol != "" && protocol != "tcp" {
+			c.warn("port %v: only tcp ports are supported", entry)
+			continue
+		}
+		port, err := strconv.Atoi(published)
+		switch {
+		case err == nil && port > 0 && port <= 65535:
+			ports = append(ports, port)
+		case strings.Contains(published, "$"):
+			c.warn("port %v: interpolated ports are not supported", entry)
+		case strings.Contains(published, "-"):
+			c.warn("port %v: port ranges are not supported", entry)
+		default:
+			c.warn("port %v: invalid port", entry)
+		}
+	}
+	return ports
+}
+
+// shortPort splits a port in the short syntax, [HOST:][PUBLISHED:]TARGET
+// [/PROTOCOL], and returns its published part, empty when none is given.
+// Variable references are replaced by "$" first, so the colons of their
+// defaults do not split the port; an IPv6 host is in brackets.
+func shortPort(spec string) (published, protocol string, ok bool) {
+	spec = composeVariable.ReplaceAllString(spec, "$$")
+	spec, protocol, _ = strings.Cut(spec, "/")
+	if strings.HasPrefix(spec, "[") {
+		end := strings.Index(spec, "]:")
+		if end < 0 {
+			return "", "", false
+		}
+		spec = spec[end+2:]
+	}
+
+	parts := strings.Split(spec, ":")
+	switch len(parts) {
+	case 1:
+		return "", protocol, true
+	case 2, 3:
+		return parts[len(parts)-2], protocol, true
+	}
+	return "", "", false
+}
+
+func (c *composeConverter) environment(value interface{}) map[string]string {
+	env := make(map[string]string)
+	switch v := value.(type) {
+	case []interface{}:
+		for _, entry := range v {
+			// Entries without a value are taken from the host environment,
+			// which loex passes on anyway.
+			if key, val, ok := strings.Cut(fmt.Sprint(entry), "="); ok {
+				env[key] = val
+			}
+		}
+	case map[string]interface{}:
+		for key, val := range v {
+			if val != nil {
+				env[key] = fmt.Sprint(val)
+			}
+		}
+	}
+
+	if len(env) == 0 {
+		return nil
+	}
+	return env
+}
+
+func (c *composeConverter) envFiles(value interface{}) []string {
+	var files []string
+	switch v := value.(type) {
+	case string:
+		files = append(files, v)
+	case []interface{}:
+		for _, entry := range v {
+			if opts, ok := entry.(map[string]interface{}); ok {
+				files = append(files, fmt.Sprint(opts["path"]))
+			} else {
+				files = append(files, fmt.Sprint(entry))
+			}
+		}
+	}
+	return files
+}
+
+// healthcheck converts a compose healthcheck into a command check that
+// runs the test inside the container with 'docker compose exec'.
+func (c *composeConverter) healthcheck(value interface{}) *models.Healthcheck {
+	definition, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if disabled, _ := definition["disable"].(bool); disabled {
+		return nil
+	}
+
+	var argv []string
+	switch test := definition["test"].(type) {
+	case string:
+		argv = []string{"sh", "-c", test}
+	case []interface{}:
+		if len(test) == 0 {
+			return nil
+		}
+		switch fmt.Sprint(test[0]) {
+		case "NONE":
+			return nil
+		case "CMD-SHELL":
+			if len(test) > 1 {
+				argv = []string{"sh", "-c", fmt.Sprint(test[1])}
+			}
+		case "CMD":
+			for _, arg := range test[1:] {
+				argv = append(argv, fmt.Sprint(arg))
+			}
+		}
+	}
+	if len(argv) == 0 {
+		c.warn("healthcheck without a test was ignored")
+		return nil
+	}
+
+	exec := []string{"docker", "compose", "-f", c.file, "exec", "-T", c.service}
+	hc := &models.Healthcheck{Command: process.QuoteCommand(append(exec, argv...))}
+
+	durations := []struct {
+		key    string
+		target *models.Duration
+	}{{"interval", &hc.Interval}, {"timeout", &hc.Timeout}}
+	for _, d := range durations {
+		if definition[d.key] == nil {
+			continue
+		}
+		parsed, err := time.ParseDuration(fmt.Sprint(definition[d.key]))
+		if err != nil {
+			c.warn("healthcheck %s %v is not a valid duration", d.key, definition[d.key])
+			continue
+		}
+		*d.target = models.Duration(parsed)
+	}
+
+	if retries, ok := definition["retries"].(int); ok {
+		hc.Retries = retries
+		// loex has no grace period, so attempts during start_period are
+		// added to the retries.
+		if startPeriod, err := time.ParseDuration(fmt.Sprint(definition["start_period"])); err == nil && hc.Interval > 0 {
+			hc.Retries += int((startPeriod + time.Duration(hc.Interval) - 1) / time.Duration(hc.Interval))
+		}
+	}
+	if definition["start_interval"] != nil {
+		c.warn("healthcheck start_interval is not supported")
+	}
+
+	return hc
+}
@@ -0,0 +1,131 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCompose = `services:
+  db:
+    image: postgres:15
+    ports:
+      - "5432:5432"
+      - "127.0.0.1:9187:9187/udp"
+    environment:
+      POSTGRES_PASSWORD: secret
+    healthcheck:
+      test: ["CMD-SHELL", "pg_isready -U postgres"]
+      interval: 5s
+      retries: 5
+      start_period: 10s
+  api:
+    build: .
+    depends_on:
+      db:
+        condition: service_healthy
+    ports:
+      - target: 8080
+        published: 8080
+      - "3000-3001:3000-3001"
+    environment:
+      - DB_HOST=db
+      - HOME
+    env_file: .env
+    profiles: [backend]
+`
+
+func TestParseCompose(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "docker-compose.yml")
+	if err := os.WriteFile(path, []byte(testCompose), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := ParseCompose(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := imported.Services["db"]
+	if db.Command != "docker compose -f docker-compose.yml up --no-deps db" || db.Dir != dir {
+		t.Errorf("Unexpected db command %q in %s", db.Command, db.Dir)
+	}
+	if !reflect.DeepEqual(db.Ports, []int{5432}) || db.Env["POSTGRES_PASSWORD"] != "secret" {
+		t.Errorf("Unexpected db ports %v or env %v", db.Ports, db.Env)
+	}
+	if db.Healthcheck == nil {
+		t.Fatal("Expected db healthcheck")
+	}
+	if db.Healthcheck.Command != "docker compose -f docker-compose.yml exec -T db sh -c 'pg_isready -U postgres'" {
+		t.Errorf("Unexpected healthcheck command %q", db.Healthcheck.Command)
+	}
+	if time.Duration(db.Healthcheck.Interval) != 5*time.Second || db.Healthcheck.Retries != 7 {
+		t.Errorf("Unexpected healthcheck timing: %+v", db.Healthcheck)
+	}
+
+	api := imported.Services["api"]
+	if !reflect.DeepEqual(api.DependsOn, []string{"db"}) || !reflect.DeepEqual(api.Ports, []int{8080}) {
+		t.Errorf("Unexpected api depends_on %v or ports %v", api.DependsOn, api.Ports)
+	}
+	if !reflect.DeepEqual(api.Env, map[string]string{"DB_HOST": "db"}) || !reflect.DeepEqual(api.EnvFiles, []string{".env"}) {
+		t.Errorf("Unexpected api env %v or env files %v", api.Env, api.EnvFiles)
+	}
+
+	warnings := strings.Join(imported.Warnings, "\n")
+	for _, expected := range []string{"api: 'profiles' is not supported", "port ranges", "only tcp ports"} {
+		if !strings.Contains(warnings, expected) {
+			t.Errorf("Expected warning containing %q, got:\n%s", expected, warnings)
+		}
+	}
+}
+
+func TestParseComposePorts(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "compose.yaml")
+	compose := `services:
+  web:
+    image: nginx
+    ports:
+      - "8080:80"
+      - "127.0.0.1:8443:443"
+      - "[::1]:9000:9000"
+      - "[::1]::9001"
+      - "5000"
+      - "${WEB_PORT:-3000}:3000"
+      - "4000:${TARGET_PORT:-4000}"
+      - "3000-3001:3000-3001"
+      - "::1:9002:9002"
+      - target: 80
+        published: "${ALT_PORT}"
+`
+	if err := os.WriteFile(path, []byte(compose), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := ParseCompose(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ports := imported.Services["web"].Ports; !reflect.DeepEqual(ports, []int{8080, 8443, 9000, 4000}) {
+		t.Errorf("Unexpected ports %v", ports)
+	}
+
+	warnings := strings.Join(imported.Warnings, "\n")
+	for _, expected := range []string{
+		"port ${WEB_PORT:-3000}:3000: interpolated ports are not supported",
+		"port 3000-3001:3000-3001: port ranges are not supported",
+		"port ::1:9002:9002: invalid port",
+	} {
+		if !strings.Contains(warnings, expected) {
+			t.Errorf("Expected warning containing %q, got:\n%s", expected, warnings)
+		}
+	}
+	if strings.Count(warnings, "interpolated") != 2 || len(imported.Warnings) != 4 {
+		t.Errorf("Unexpected warnings:\n%s", warnings)
+	}
+}
@@ -1,7 +1,6 @@
 package detector
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,219 +11,70 @@ import (
 	"github.com/kjunh972/loex/pkg/models"
 )
 
-type ServiceDetector struct{}
+// ServiceDetector proposes services for directories by evaluating
+// detection rules.
+type ServiceDetector struct {
+	rules []Rule
+}
 
+// New returns a detector using the built-in rules.
 func New() *ServiceDetector {
-	return &ServiceDetector{}
+	return &ServiceDetector{rules: BuiltinRules()}
+}
+
+// NewWithRules returns a detector using rules in precedence order, as
+// returned by LoadRules.
+func NewWithRules(rules []Rule) *ServiceDetector {
+	return &ServiceDetector{rules: rules}
 }
 
+// DetectionResult is a proposed service. Name is the suggested service
+// name and Kind the label describing what was detected. Dir, the directory
+// the service runs in, is set by DetectRecursive. Rule names the detection
+// rule that proposed the service, with a Confidence up to MaxConfidence;
+// Alternatives are the other candidates for the service, best first.
 type DetectionResult struct {
-	Service         models.ServiceType
+	Name            string
+	Kind            models.ServiceType
 	Command         string
+	Dir             string
+	EnvFiles        []string
+	Ports           []int
+	Env             map[string]string
+	Rule            string
+	Confidence      int
+	Alternatives    []DetectionResult
 	DetectionReason string
 }
 
 func (d *ServiceDetector) DetectServices(dir string) ([]DetectionResult, error) {
-	var results []DetectionResult
-
-	if frontend := d.detectFrontend(dir); frontend != nil {
-		results = append(results, *frontend)
-	}
-
-	if backend := d.detectBackend(dir); backend != nil {
-		results = append(results, *backend)
+	// A Procfile declares the processes of the project explicitly.
+	if results, err := d.detectProcfile(dir); err != nil || len(results) > 0 {
+		return results, err
 	}
 
-	if db := d.detectDatabase(dir); db != nil {
-		results = append(results, *db)
-	}
-
-	return results, nil
+	return d.detectRules(dir, true), nil
 }
 
-func (d *ServiceDetector) detectFrontend(dir string) *DetectionResult {
-	packageJSONPath := filepath.Join(dir, "package.json")
-	
-	if !fileExists(packageJSONPath) {
-		return nil
-	}
-
-	data, err := os.ReadFile(packageJSONPath)
-	if err != nil {
-		return nil
-	}
-
-	var packageJSON map[string]interface{}
-	if err := json.Unmarshal(data, &packageJSON); err != nil {
-		return nil
-	}
-
-	deps := extractDependencies(packageJSON)
-	
-	if contains(deps, "react") {
-		if contains(deps, "react-native") {
-			return &DetectionResult{
-				Service:         models.ServiceFrontend,
-				Command:         "npx react-native start",
-				DetectionReason: "Detected React Native project",
-			}
-		}
-		return &DetectionResult{
-			Service:         models.ServiceFrontend,
-			Command:         "npm start",
-			DetectionReason: "Detected React project",
+// DatabaseHint explains how to install a database when the project in dir
+// is configured for one but results hold no database service. It returns
+// an empty string otherwise.
+func (d *ServiceDetector) DatabaseHint(dir string, results []DetectionResult) string {
+	for _, result := range results {
+		if result.Kind == models.ServiceDB {
+			return ""
 		}
 	}
-
-	if contains(deps, "vue") {
-		return &DetectionResult{
-			Service:         models.ServiceFrontend,
-			Command:         "npm run dev",
-			DetectionReason: "Detected Vue.js project",
-		}
-	}
-
-	if contains(deps, "@angular/core") {
-		return &DetectionResult{
-			Service:         models.ServiceFrontend,
-			Command:         "npm start",
-			DetectionReason: "Detected Angular project",
-		}
-	}
-
-	if contains(deps, "next") {
-		return &DetectionResult{
-			Service:         models.ServiceFrontend,
-			Command:         "npm run dev",
-			DetectionReason: "Detected Next.js project",
-		}
-	}
-
-	if scripts, ok := packageJSON["scripts"].(map[string]interface{}); ok {
-		if _, hasStart := scripts["start"]; hasStart {
-			return &DetectionResult{
-				Service:         models.ServiceFrontend,
-				Command:         "npm start",
-				DetectionReason: "Detected Node.js project with start script",
-			}
-		}
-		if _, hasDev := scripts["dev"]; hasDev {
-			return &DetectionResult{
-				Service:         models.ServiceFrontend,
-				Command:         "npm run dev",
-				DetectionReason: "Detected Node.js project with dev script",
-			}
-		}
-	}
-
-	return nil
-}
-
-func (d *ServiceDetector) detectBackend(dir string) *DetectionResult {
-	if fileExists(filepath.Join(dir, "go.mod")) {
-		if fileExists(filepath.Join(dir, "main.go")) {
-			return &DetectionResult{
-				Service:         models.ServiceBackend,
-				Command:         "go run main.go",
-				DetectionReason: "Detected Go project with main.go",
-			}
-		}
-		return &DetectionResult{
-			Service:         models.ServiceBackend,
-			Command:         "go run .",
-			DetectionReason: "Detected Go project",
-		}
-	}
-
-	if fileExists(filepath.Join(dir, "pom.xml")) {
-		return &DetectionResult{
-			Service:         models.ServiceBackend,
-			Command:         "mvn spring-boot:run",
-			DetectionReason: "Detected Maven project",
-		}
-	}
-
-	if fileExists(filepath.Join(dir, "build.gradle")) || fileExists(filepath.Join(dir, "build.gradle.kts")) {
-		return &DetectionResult{
-			Service:         models.ServiceBackend,
-			Command:         "./gradlew bootRun",
-			DetectionReason: "Detected Gradle project",
-		}
-	}
-
-	if fileExists(filepath.Join(dir, "requirements.txt")) || fileExists(filepath.Join(dir, "pyproject.toml")) {
-		if fileExists(filepath.Join(dir, "manage.py")) {
-			return &DetectionResult{
-				Service:         models.ServiceBackend,
-				Command:         "python manage.py runserver",
-				DetectionReason: "Detected Django project",
-			}
-		}
-		if fileExists(filepath.Join(dir, "app.py")) {
-			return &DetectionResult{
-				Service:         models.ServiceBackend,
-				Command:         "python app.py",
-				DetectionReason: "Detected Python Flask/FastAPI project",
-			}
-		}
-	}
-
-	if fileExists(filepath.Join(dir, "Cargo.toml")) {
-		return &DetectionResult{
-			Service:         models.ServiceBackend,
-			Command:         "cargo run",
-			DetectionReason: "Detected Rust project",
-		}
-	}
-
-	jarFiles, _ := filepath.Glob(filepath.Join(dir, "*.jar"))
-	if len(jarFiles) > 0 {
-		return &DetectionResult{
-			Service:         models.ServiceBackend,
-			Command:         fmt.Sprintf("java -jar %s", filepath.Base(jarFiles[0])),
-			DetectionReason: "Detected JAR file",
-		}
-	}
-
-	return nil
-}
-
-func (d *ServiceDetector) detectDatabase(dir string) *DetectionResult {
-	if fileExists(filepath.Join(dir, "docker-compose.yml")) || fileExists(filepath.Join(dir, "docker-compose.yaml")) {
-		return &DetectionResult{
-			Service:         models.ServiceDB,
-			Command:         "docker-compose up -d",
-			DetectionReason: "Detected docker-compose.yml",
-		}
-	}
-
-	if fileExists(filepath.Join(dir, "Dockerfile")) {
-		return &DetectionResult{
-			Service:         models.ServiceDB,
-			Command:         "docker build -t local-db . && docker run -d local-db",
-			DetectionReason: "Detected Dockerfile",
-		}
+	if !d.hasDBConfigFiles(dir) {
+		return ""
 	}
 
+	install := "To install MySQL via Docker:\n  docker pull mysql:8.0"
 	if runtime.GOOS == "darwin" {
-		if dbService := d.detectBrewDatabaseServices(); dbService != nil {
-			return dbService
-		}
+		install = "To install MySQL:\n  brew install mysql"
 	}
-
-	if d.hasDBConfigFiles(dir) {
-		fmt.Println("\nDatabase configuration detected but no database service found.")
-		if runtime.GOOS == "darwin" {
-			fmt.Println("To install MySQL:")
-			fmt.Println("  brew install mysql")
-		} else {
-			fmt.Println("To install MySQL via Docker:")
-			fmt.Println("  docker pull mysql:8.0")
-		}
-		fmt.Println("Then run 'loex config detect' again to register the database service.")
-	}
-
-	return nil
+	return "Database configuration detected but no database service found.\n" + install +
+		"\nThen run 'loex config detect' again to register the database service."
 }
 
 func extractDependencies(packageJSON map[string]interface{}) []string {
@@ -245,21 +95,14 @@ func extractDependencies(packageJSON map[string]interface{}) []string {
 	return deps
 }
 
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if strings.Contains(s, item) {
-			return true
-		}
-	}
-	return false
-}
-
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
 
-func (d *ServiceDetector) detectBrewDatabaseServices() *DetectionResult {
+// detectBrewDatabaseServices proposes a MySQL or PostgreSQL service
+// installed with Homebrew.
+func detectBrewDatabaseServices() *DetectionResult {
 	cmd := exec.Command("brew", "services", "list")
 	output, err := cmd.Output()
 	if err != nil {
@@ -277,7 +120,8 @@ func (d *ServiceDetector) detectBrewDatabaseServices() *DetectionResult {
 		
 		if strings.Contains(serviceName, "mysql") {
 			return &DetectionResult{
-				Service:         models.ServiceDB,
+				Name:            string(models.ServiceDB),
+				Kind:            models.ServiceDB,
 				Command:         fmt.Sprintf("brew services start %s", serviceName),
 				DetectionReason: fmt.Sprintf("Detected %s via Homebrew", serviceName),
 			}
@@ -285,7 +129,8 @@ func (d *ServiceDetector) detectBrewDatabaseServices() *DetectionResult {
 		
 		if strings.Contains(serviceName, "postgresql") || strings.Contains(serviceName, "postgres") {
 			return &DetectionResult{
-				Service:         models.ServiceDB,
+				Name:            string(models.ServiceDB),
+				Kind:            models.ServiceDB,
 				Command:         fmt.Sprintf("brew services start %s", serviceName),
 				DetectionReason: fmt.Sprintf("Detected %s via Homebrew", serviceName),
 			}
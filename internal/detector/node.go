@@ -0,0 +1,147 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Node.js package managers.
+const (
+	NPM  = "npm"
+	Yarn = "yarn"
+	PNPM = "pnpm"
+	Bun  = "bun"
+)
+
+// DevScripts are the package.json scripts proposed to run a Node.js
+// project, in order of preference.
+var DevScripts = []string{"dev", "start", "serve"}
+
+// lockfiles maps lockfile names to their package manager, in order of
+// precedence when several are present.
+var lockfiles = []struct{ name, manager string }{
+	{"pnpm-lock.yaml", PNPM},
+	{"yarn.lock", Yarn},
+	{"bun.lockb", Bun},
+	{"bun.lock", Bun},
+	{"package-lock.json", NPM},
+	{"npm-shrinkwrap.json", NPM},
+}
+
+// PackageManager is the package manager of a Node.js project. Source names
+// what it was detected from and Root is the directory of that file, the
+// root of the workspace for workspace members.
+type PackageManager struct {
+	Name   string
+	Source string
+	Root   string
+}
+
+// Run returns the command running a package.json script.
+func (pm PackageManager) Run(script string) string {
+	switch pm.Name {
+	case Yarn, PNPM:
+		return pm.Name + " " + script
+	case Bun:
+		return "bun run " + script
+	}
+	if script == "start" {
+		return "npm start"
+	}
+	return "npm run " + script
+}
+
+// Exec returns the command running a binary of an installed package.
+func (pm PackageManager) Exec(command string) string {
+	switch pm.Name {
+	case Yarn:
+		return "yarn " + command
+	case PNPM:
+		return "pnpm exec " + command
+	case Bun:
+		return "bunx " + command
+	}
+	return "npx " + command
+}
+
+// DetectPackageManager finds the package manager of the Node.js project in
+// dir from the packageManager field of package.json or from lockfiles,
+// looking in dir and then its parents up to the repository root. npm is
+// assumed when nothing is found.
+func DetectPackageManager(dir string) PackageManager {
+	for current := dir; ; current = filepath.Dir(current) {
+		if data, err := os.ReadFile(filepath.Join(current, "package.json")); err == nil {
+			var packageJSON struct {
+				PackageManager string `json:"packageManager"`
+			}
+			if json.Unmarshal(data, &packageJSON) == nil && packageJSON.PackageManager != "" {
+				// The field is name@version, e.g. pnpm@9.1.0.
+				name, _, _ := strings.Cut(packageJSON.PackageManager, "@")
+				return PackageManager{Name: name, Source: "packageManager in package.json", Root: current}
+			}
+		}
+
+		for _, lockfile := range lockfiles {
+			if fileExists(filepath.Join(current, lockfile.name)) {
+				return PackageManager{Name: lockfile.manager, Source: lockfile.name, Root: current}
+			}
+		}
+
+		if fileExists(filepath.Join(current, ".git")) || filepath.Dir(current) == current {
+			return PackageManager{Name: NPM, Root: dir}
+		}
+	}
+}
+
+// nodeFramework is a Node.js framework recognized from the dependencies
+// of package.json. CLI, when set, starts the development server of a
+// project without a dev script.
+type nodeFramework struct {
+	Label string
+	CLI   string
+}
+
+// devScript returns the first of DevScripts defined in package.json.
+func devScript(packageJSON map[string]interface{}) string {
+	scripts, _ := packageJSON["scripts"].(map[string]interface{})
+	for _, script := range DevScripts {
+		if _, ok := scripts[script]; ok {
+			return script
+		}
+	}
+	return ""
+}
+
+// nodeCommand returns the command and reason for running the Node.js
+// project in dir. The dev script is preferred; without one, the project is
+// started through the CLI of its framework. The reason notes the package
+// manager and whether dependencies still have to be installed.
+func nodeCommand(dir string, packageJSON map[string]interface{}, framework nodeFramework) (string, string, bool) {
+	pm := DetectPackageManager(dir)
+
+	var command, reason string
+	if script := devScript(packageJSON); script != "" {
+		command = pm.Run(script)
+		if framework.Label != "" {
+			reason = fmt.Sprintf("Detected %s project", framework.Label)
+		} else {
+			reason = fmt.Sprintf("Detected Node.js project with %s script", script)
+		}
+	} else if framework.CLI != "" {
+		command = pm.Exec(framework.CLI)
+		reason = fmt.Sprintf("Detected %s project without a dev script", framework.Label)
+	} else {
+		return "", "", false
+	}
+
+	if pm.Source != "" {
+		reason += fmt.Sprintf(", using %s (%s)", pm.Name, pm.Source)
+	}
+	if !fileExists(filepath.Join(dir, "node_modules")) && !fileExists(filepath.Join(pm.Root, "node_modules")) {
+		reason += fmt.Sprintf("; node_modules is missing, run '%s install' first", pm.Name)
+	}
+	return command, reason, true
+}
@@ -0,0 +1,135 @@
+package detector
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+func TestDetectPackageManager(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		dir      string
+		expected string
+	}{
+		{"npm by default", map[string]string{"package.json": `{}`}, ".", NPM},
+		{"pnpm lockfile", map[string]string{"package.json": `{}`, "pnpm-lock.yaml": ""}, ".", PNPM},
+		{"yarn lockfile", map[string]string{"package.json": `{}`, "yarn.lock": ""}, ".", Yarn},
+		{"bun lockfile", map[string]string{"package.json": `{}`, "bun.lockb": ""}, ".", Bun},
+		{"packageManager field", map[string]string{"package.json": `{"packageManager": "yarn@4.1.0"}`, "package-lock.json": ""}, ".", Yarn},
+		{"workspace root lockfile", map[string]string{
+			".git/HEAD":             "",
+			"pnpm-lock.yaml":        "",
+			"apps/web/package.json": `{}`,
+		}, "apps/web", PNPM},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, test.files)
+			if pm := DetectPackageManager(filepath.Join(root, test.dir)); pm.Name != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, pm.Name)
+			}
+		})
+	}
+}
+
+func TestDetectFrontendScripts(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		command string
+		reason  string
+	}{
+		{
+			"dev script with pnpm",
+			map[string]string{"package.json": `{"scripts": {"start": "vite preview", "dev": "vite"}, "dependencies": {"vue": "3"}}`, "pnpm-lock.yaml": "", "node_modules/.keep": ""},
+			"pnpm dev", "Detected Vue.js project, using pnpm (pnpm-lock.yaml)",
+		},
+		{
+			"start script with npm",
+			map[string]string{"package.json": `{"scripts": {"start": "react-scripts start"}, "dependencies": {"react": "18"}}`, "package-lock.json": "", "node_modules/.keep": ""},
+			"npm start", "Detected React project, using npm (package-lock.json)",
+		},
+		{
+			"serve script with bun",
+			map[string]string{"package.json": `{"scripts": {"serve": "vue-cli-service serve"}}`, "bun.lockb": "", "node_modules/.keep": ""},
+			"bun run serve", "Detected Node.js project with serve script, using bun (bun.lockb)",
+		},
+		{
+			"react native without scripts",
+			map[string]string{"package.json": `{"packageManager": "yarn@1.22.0", "dependencies": {"react-native": "0.73"}}`},
+			"yarn react-native start", "Detected React Native project without a dev script, using yarn (packageManager in package.json); node_modules is missing, run 'yarn install' first",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, test.files)
+			result := proposed(New(), dir, models.ServiceFrontend)
+			if result == nil {
+				t.Fatal("Expected a result")
+			}
+			if result.Command != test.command || result.DetectionReason != test.reason {
+				t.Errorf("Expected %q (%s), got %q (%s)", test.command, test.reason, result.Command, result.DetectionReason)
+			}
+		})
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"package.json": `{"scripts": {"build": "tsc"}, "dependencies": {"react": "18"}}`})
+	if result := proposed(New(), dir, models.ServiceFrontend); result != nil {
+		t.Errorf("Expected no result without a dev script, got %q", result.Command)
+	}
+	if command := DetectPackageManager(dir).Run("dev"); command != "npm run dev" {
+		t.Errorf("Expected npm run dev, got %q", command)
+	}
+}
+
+func TestDetectNodeFrameworks(t *testing.T) {
+	tests := []struct {
+		name    string
+		deps    string
+		scripts string
+		command string
+		label   string
+	}{
+		{"vite", `"vite": "5"`, `"dev": "vite"`, "npm run dev", "Vite"},
+		{"vite without script", `"vite": "5"`, `"build": "vite build"`, "npx vite", "Vite"},
+		{"react with vite", `"react": "18", "react-dom": "18", "vite": "5"`, `"dev": "vite"`, "npm run dev", "React with Vite"},
+		{"vue with vite", `"vue": "3", "vite": "5"`, `"build": "vite build"`, "npx vite", "Vue.js with Vite"},
+		{"nuxt", `"nuxt": "3", "vue": "3"`, `"dev": "nuxt dev"`, "npm run dev", "Nuxt"},
+		{"nuxt without script", `"nuxt": "3", "vue": "3"`, `"build": "nuxt build"`, "npx nuxt dev", "Nuxt"},
+		{"sveltekit", `"@sveltejs/kit": "2", "svelte": "4", "vite": "5"`, `"build": "vite build"`, "npx vite dev", "SvelteKit"},
+		{"remix", `"@remix-run/node": "2", "@remix-run/react": "2", "react": "18"`, `"build": "remix build"`, "npx remix dev", "Remix"},
+		{"remix with vite", `"@remix-run/react": "2", "react": "18", "vite": "5"`, `"build": "remix vite:build"`, "npx remix vite:dev", "Remix"},
+		{"astro", `"astro": "4"`, `"dev": "astro dev"`, "npm run dev", "Astro"},
+		{"angular without script", `"@angular/core": "17"`, `"build": "ng build"`, "npx ng serve", "Angular"},
+		{"next", `"next": "14", "react": "18"`, `"dev": "next dev"`, "npm run dev", "Next.js"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				"package.json":       `{"scripts": {` + test.scripts + `}, "dependencies": {` + test.deps + `}}`,
+				"node_modules/.keep": "",
+			})
+			result := proposed(New(), dir, models.ServiceFrontend)
+			if result == nil {
+				t.Fatal("Expected a result")
+			}
+			if result.Command != test.command {
+				t.Errorf("Expected %q, got %q", test.command, result.Command)
+			}
+			if reason := "Detected " + test.label + " project"; !strings.HasPrefix(result.DetectionReason, reason) {
+				t.Errorf("Expected reason %q, got %q", reason, result.DetectionReason)
+			}
+		})
+	}
+}
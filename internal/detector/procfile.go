@@ -0,0 +1,235 @@
+package detector
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kjunh972/loex/internal/env"
+	"github.com/kjunh972/loex/internal/process"
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+// ProcfileNames are the Procfiles looked for by detection, in order of
+// preference. Procfile.dev is the local development variant used by
+// Rails and others.
+var ProcfileNames = []string{"Procfile.dev", "Procfile"}
+
+var procfileLine = regexp.MustCompile(`^([A-Za-z0-9_-]+):\s*(.+)$`)
+
+// ProcfileEntry is a process type declared in a Procfile.
+type ProcfileEntry struct {
+	Name    string
+	Command string
+}
+
+// ParseProcfile reads a Procfile and returns its entries in file order.
+func ParseProcfile(path string) ([]ProcfileEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Procfile: %w", err)
+	}
+	defer file.Close()
+
+	var entries []ProcfileEntry
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		match := procfileLine.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("%s:%d: expected 'name: command'", path, lineNumber)
+		}
+		if seen[match[1]] {
+			return nil, fmt.Errorf("%s:%d: duplicate process type '%s'", path, lineNumber, match[1])
+		}
+		seen[match[1]] = true
+		entries = append(entries, ProcfileEntry{Name: match[1], Command: strings.TrimSpace(match[2])})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Procfile: %w", err)
+	}
+
+	return entries, nil
+}
+
+// detectProcfile proposes one service per entry of the Procfile in dir.
+// Like foreman, a .env file next to the Procfile is loaded by each service.
+// A Procfile that cannot be parsed is an error rather than a reason to fall
+// back to the rules, so the user learns why its entries were not proposed.
+func (d *ServiceDetector) detectProcfile(dir string) ([]DetectionResult, error) {
+	for _, name := range ProcfileNames {
+		path := filepath.Join(dir, name)
+		if !fileExists(path) {
+			continue
+		}
+
+		entries, err := ParseProcfile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var envFiles []string
+		if fileExists(filepath.Join(dir, ".env")) {
+			envFiles = []string{".env"}
+		}
+
+		var results []DetectionResult
+		for _, entry := range entries {
+			results = append(results, DetectionResult{
+				Name:            entry.Name,
+				Command:         entry.Command,
+				EnvFiles:        envFiles,
+				Confidence:      MaxConfidence,
+				DetectionReason: fmt.Sprintf("Defined in %s", name),
+			})
+		}
+		return results, nil
+	}
+	return nil, nil
+}
+
+// FormatProcfile renders the services of a project as a Procfile to be
+// placed in dir. Services in other directories are prefixed with a cd,
+// project and service variables become inline assignments and env files
+// are listed in a header comment, since foreman and overmind take them
+// with -e. Procfiles are usually committed, so variables that look like
+// secrets are left to env files, and values referencing other variables
+// are left out since loex interpolates them at start. The returned
+// warnings describe what was not exported.
+func FormatProcfile(project *models.Project, dir string) (string, []string) {
+	var b strings.Builder
+	var warnings []string
+
+	envFiles := exportEnvFiles(project, dir)
+	if len(envFiles) > 0 {
+		fmt.Fprintf(&b, "# Env files: %s\n", strings.Join(envFiles, ","))
+		fmt.Fprintf(&b, "# Run with: foreman start -e %s\n", strings.Join(envFiles, ","))
+	}
+
+	for _, serviceName := range project.ServiceNames() {
+		service := project.Services[serviceName]
+
+		command := service.Command
+		if len(service.Args) > 0 {
+			command = process.QuoteCommand(service.Args)
+		} else if service.Shell != "" {
+			command = process.QuoteCommand([]string{service.Shell, "-c", service.Command})
+		}
+
+		vars := make(map[string]string)
+		for key, value := range project.Env {
+			vars[key] = value
+		}
+		for key, value := range service.Env {
+			vars[key] = value
+		}
+		portNames := make([]string, 0, len(service.NamedPorts))
+		for name := range service.NamedPorts {
+			portNames = append(portNames, name)
+		}
+		sort.Strings(portNames)
+		for _, name := range portNames {
+			if port, ok := service.NamedPorts[name].Number(); ok {
+				vars[name] = strconv.Itoa(port)
+			} else {
+				warnings = append(warnings, fmt.Sprintf("%s: auto port %s was not exported", serviceName, name))
+			}
+		}
+		keys := make([]string, 0, len(vars))
+		for key := range vars {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var assignments []string
+		for _, key := range keys {
+			if env.IsSecret(key) {
+				warnings = append(warnings, fmt.Sprintf("%s: %s looks like a secret, set it in an env file passed with -e", serviceName, key))
+				continue
+			}
+			value, interpolated := expandLiteral(vars[key])
+			if interpolated {
+				warnings = append(warnings, fmt.Sprintf("%s: %s references other variables, which loex interpolates at start", serviceName, key))
+				continue
+			}
+			assignments = append(assignments, key+"="+process.QuoteCommand([]string{value}))
+		}
+		if len(assignments) > 0 {
+			command = strings.Join(assignments, " ") + " " + command
+		}
+
+		if serviceDir := relativeDir(dir, service.Dir); serviceDir != "." {
+			command = fmt.Sprintf("cd %s && %s", process.QuoteCommand([]string{serviceDir}), command)
+		}
+
+		fmt.Fprintf(&b, "%s: %s\n", serviceName, command)
+
+		if len(service.DependsOn) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s: depends_on is not supported by Procfiles, all processes start together", serviceName))
+		}
+		if service.Healthcheck != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: health check was not exported", serviceName))
+		}
+	}
+
+	return b.String(), warnings
+}
+
+// expandLiteral returns value with escaped dollar signs unescaped, and
+// whether it references variables.
+func expandLiteral(value string) (string, bool) {
+	interpolated := false
+	expanded := env.Expand(value, func(string) (string, bool) {
+		interpolated = true
+		return "", false
+	})
+	return expanded, interpolated
+}
+
+// exportEnvFiles returns the env files of all services relative to dir, in
+// the order they are loaded.
+func exportEnvFiles(project *models.Project, dir string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(serviceDir, file string) {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(serviceDir, file)
+		}
+		file = relativeDir(dir, file)
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, serviceName := range project.ServiceNames() {
+		service := project.Services[serviceName]
+		for _, file := range project.EnvFiles {
+			add(service.Dir, file)
+		}
+		for _, file := range service.EnvFiles {
+			add(service.Dir, file)
+		}
+	}
+	return files
+}
+
+// relativeDir returns path relative to base when possible.
+func relativeDir(base, path string) string {
+	if path == "" {
+		return "."
+	}
+	if rel, err := filepath.Rel(base, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,102 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+func TestDetectProcfile(t *testing.T) {
+	dir := t.TempDir()
+	procfile := "# processes\nweb: bundle exec rails server -p $PORT\n\nworker:   bundle exec sidekiq\n"
+	if err := os.WriteFile(filepath.Join(dir, "Procfile"), []byte(procfile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("PORT=3000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The Procfile takes precedence over other detection.
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"scripts":{"start":"node ."}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := New().DetectServices(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, result := range results {
+		names = append(names, result.Name+": "+result.Command)
+		if !reflect.DeepEqual(result.EnvFiles, []string{".env"}) {
+			t.Errorf("%s: expected .env to be loaded, got %v", result.Name, result.EnvFiles)
+		}
+	}
+	expected := []string{"web: bundle exec rails server -p $PORT", "worker: bundle exec sidekiq"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
+
+func TestParseProcfileErrors(t *testing.T) {
+	dir := t.TempDir()
+	for _, content := range []string{"web npm start\n", "web: a\nweb: b\n"} {
+		path := filepath.Join(dir, "Procfile")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ParseProcfile(path); err == nil {
+			t.Errorf("Expected an error for %q", content)
+		}
+	}
+
+	// Detection reports the error instead of falling back to the rules.
+	writeFiles(t, dir, map[string]string{"Procfile": "web npm start\n", "go.mod": "module example.com/app\n"})
+	if _, err := New().DetectServices(dir); err == nil || !strings.Contains(err.Error(), "Procfile:1: expected 'name: command'") {
+		t.Errorf("Expected the Procfile error, got %v", err)
+	}
+}
+
+func TestFormatProcfile(t *testing.T) {
+	project := &models.Project{
+		EnvFiles: []string{".env"},
+		Services: map[string]models.Service{
+			"api": {Command: "go run .", Dir: "/repo/backend", Env: map[string]string{
+				"PORT":     "8080",
+				"GREETING": "hello world",
+				"PRICE":    `\$5`,
+				"API_KEY":  "s3cret",
+				"DB_URL":   "postgres://${DB_HOST}/app",
+			}},
+			"web": {Command: "npm run dev", Dir: "/repo", DependsOn: []string{"api"}},
+			"job": {Args: []string{"python", "job.py", "--name", "a b"}, Dir: "/repo", EnvFiles: []string{"job.env"}},
+		},
+	}
+
+	content, warnings := FormatProcfile(project, "/repo")
+
+	expected := strings.Join([]string{
+		"# Env files: backend/.env,.env,job.env",
+		"# Run with: foreman start -e backend/.env,.env,job.env",
+		"api: cd backend && GREETING='hello world' PORT=8080 PRICE='$5' go run .",
+		"job: python job.py --name 'a b'",
+		"web: npm run dev",
+		"",
+	}, "\n")
+	if content != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, content)
+	}
+	expectedWarnings := []string{"api: API_KEY looks like a secret", "api: DB_URL references other variables", "web: depends_on"}
+	if len(warnings) != len(expectedWarnings) {
+		t.Fatalf("Unexpected warnings: %v", warnings)
+	}
+	for i, warning := range warnings {
+		if !strings.HasPrefix(warning, expectedWarnings[i]) {
+			t.Errorf("Expected warning %q, got %q", expectedWarnings[i], warning)
+		}
+	}
+}
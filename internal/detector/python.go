@@ -0,0 +1,60 @@
+package detector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// fastAPIEntrypoints are the modules searched for a FastAPI app, relative
+// to the project directory.
+var fastAPIEntrypoints = []string{"main.py", "app.py", "app/main.py", "src/main.py", "api/main.py"}
+
+var fastAPIApp = regexp.MustCompile(`(?m)^([A-Za-z_]\w*)\s*(?::[^=\n]+)?=\s*FastAPI\(`)
+
+// pythonManager returns the tool managing the virtual environment of the
+// Python project in dir, uv, Poetry or Pipenv, and the file it was
+// detected from. Commands of managed projects run through "<tool> run".
+func pythonManager(dir string) (string, string) {
+	if fileExists(filepath.Join(dir, "uv.lock")) {
+		return "uv", "uv.lock"
+	}
+	if fileExists(filepath.Join(dir, "poetry.lock")) {
+		return "poetry", "poetry.lock"
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "pyproject.toml")); err == nil && strings.Contains(string(data), "[tool.poetry]") {
+		return "poetry", "pyproject.toml"
+	}
+	if fileExists(filepath.Join(dir, "Pipfile")) {
+		return "pipenv", "Pipfile"
+	}
+	return "", ""
+}
+
+// findFastAPIApp returns the uvicorn import string of the FastAPI app of
+// the project in dir, e.g. "app.main:app".
+func findFastAPIApp(dir string) string {
+	for _, entrypoint := range fastAPIEntrypoints {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(entrypoint)))
+		if err != nil {
+			continue
+		}
+		if match := fastAPIApp.FindSubmatch(data); match != nil {
+			module := strings.ReplaceAll(strings.TrimSuffix(entrypoint, ".py"), "/", ".")
+			return module + ":" + string(match[1])
+		}
+	}
+	return ""
+}
+
+// pythonResult runs command in the virtual environment of the project
+// manager of dir, if any.
+func pythonResult(dir, command, reason string) *DetectionResult {
+	if manager, source := pythonManager(dir); manager != "" {
+		command = manager + " run " + command
+		reason += fmt.Sprintf(", using %s (%s)", manager, source)
+	}
+	return backendResult(command, reason)
+}
@@ -0,0 +1,545 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kjunh972/loex/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// RulesDir is the directory holding rule files, below the loex config
+// directory for user rules and below .loex in a repository for rules of
+// that repository.
+const RulesDir = "detectors.d"
+
+// SourceBuiltin is the source of the rules shipped with loex.
+const SourceBuiltin = "built-in"
+
+// MaxConfidence is the confidence of services declared explicitly, by a
+// Procfile or by rule files that do not set one.
+const MaxConfidence = 100
+
+// Rule proposes a service when all of its conditions match a directory.
+// Rules with the same kind compete: the matching rule with the highest
+// confidence proposes the service of that kind, the others become its
+// alternatives. Ties go to the rule of higher precedence.
+type Rule struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Match       Match  `yaml:"match"`
+	// Confidence ranks the rule against other matching rules, from 1 to
+	// MaxConfidence.
+	Confidence int `yaml:"confidence,omitempty"`
+
+	// Service is the proposed service name, by default the kind or, for
+	// rules without kind, the rule name.
+	Service string             `yaml:"service,omitempty"`
+	Kind    models.ServiceType `yaml:"kind,omitempty"`
+	Command string             `yaml:"command,omitempty"`
+	Ports   []int              `yaml:"ports,omitempty"`
+	Env     map[string]string  `yaml:"env,omitempty"`
+
+	// Source is the file the rule was loaded from, or SourceBuiltin.
+	Source string `yaml:"-"`
+
+	// resolve derives the command of built-in rules from the directory. It
+	// returns nil when no command can be proposed after all.
+	resolve func(dir string) *DetectionResult
+	// rootOnly rules look at the system rather than the directory and are
+	// skipped below the root of a recursive scan.
+	rootOnly bool
+}
+
+// Match holds the conditions of a rule. Paths are relative to the
+// directory being detected and globs use filepath.Match syntax.
+type Match struct {
+	// Files must all exist.
+	Files []string `yaml:"files,omitempty"`
+	// At least one of AnyFiles must exist.
+	AnyFiles []string `yaml:"any_files,omitempty"`
+	// Globs must each match at least one file.
+	Globs []string `yaml:"globs,omitempty"`
+	// Dependencies must each be in dependencies or devDependencies of
+	// package.json; names may be globs such as "@remix-run/*".
+	Dependencies []string `yaml:"dependencies,omitempty"`
+	// PackageJSON values must exist at a dotted path such as
+	// "scripts.dev", and match Regex when one is given.
+	PackageJSON []JSONMatch `yaml:"package_json,omitempty"`
+	// Contents must each be found in one of their files.
+	Contents []ContentMatch `yaml:"contents,omitempty"`
+}
+
+// JSONMatch checks a value of package.json.
+type JSONMatch struct {
+	Path  string `yaml:"path"`
+	Regex string `yaml:"regex,omitempty"`
+}
+
+// ContentMatch looks for a regular expression in the files matching any of
+// Files.
+type ContentMatch struct {
+	Files []string `yaml:"files"`
+	Regex string   `yaml:"regex"`
+}
+
+// Condition is the outcome of one condition of a rule.
+type Condition struct {
+	Description string
+	Matched     bool
+}
+
+// RuleMatch is the evaluation of a rule against a directory. Result is set
+// when all conditions matched and the rule proposed a service; Selected
+// when that service ranks first for its kind. Otherwise Preferred names the
+// rule that does.
+type RuleMatch struct {
+	Rule       Rule
+	Conditions []Condition
+	Matched    bool
+	Result     *DetectionResult
+	Selected   bool
+	Preferred  string
+}
+
+type rulesFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// LoadRules returns the active rules in precedence order: the rules of the
+// repository holding projectDir, then the user rules in userDir, then the
+// built-in rules. A rule replaces rules of lower precedence with the same
+// name. Missing directories are skipped.
+func LoadRules(userDir, projectDir string) ([]Rule, error) {
+	var rules []Rule
+	for _, dir := range []string{findProjectRulesDir(projectDir, userDir), userDir} {
+		if dir == "" {
+			continue
+		}
+		loaded, err := loadRulesDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, loaded...)
+	}
+	rules = append(rules, BuiltinRules()...)
+
+	seen := make(map[string]bool)
+	active := rules[:0]
+	for _, rule := range rules {
+		if seen[rule.Name] {
+			continue
+		}
+		seen[rule.Name] = true
+		active = append(active, rule)
+	}
+	return active, nil
+}
+
+// findProjectRulesDir looks for .loex/detectors.d in dir and its parents
+// up to the repository root. The user rules directory is not a project
+// one even when a project lives below the home directory.
+func findProjectRulesDir(dir, userDir string) string {
+	for current := dir; current != ""; current = filepath.Dir(current) {
+		rulesDir := filepath.Join(current, ".loex", RulesDir)
+		if isDir(rulesDir) && rulesDir != userDir {
+			return rulesDir
+		}
+		if fileExists(filepath.Join(current, ".git")) || filepath.Dir(current) == current {
+			break
+		}
+	}
+	return ""
+}
+
+// loadRulesDir reads the *.yaml and *.yml files of dir in name order.
+func loadRulesDir(dir string) ([]Rule, error) {
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	var rules []Rule
+	for _, file := range files {
+		loaded, err := ReadRulesFile(file)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, loaded...)
+	}
+	return rules, nil
+}
+
+// ReadRulesFile reads and validates the rules of a YAML file with a
+// top-level rules list.
+func ReadRulesFile(file string) ([]Rule, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var parsed rulesFile
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", file, err)
+	}
+
+	names := make(map[string]bool)
+	for i := range parsed.Rules {
+		rule := &parsed.Rules[i]
+		rule.Source = file
+		if rule.Confidence == 0 {
+			rule.Confidence = MaxConfidence
+		}
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("%s: rule '%s' is defined twice", file, rule.Name)
+		}
+		names[rule.Name] = true
+	}
+	return parsed.Rules, nil
+}
+
+// Validate checks that a rule has a name, a command, at least one
+// condition and valid patterns.
+func (r *Rule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule without name")
+	}
+	if r.Command == "" && r.resolve == nil {
+		return fmt.Errorf("rule '%s' has no command", r.Name)
+	}
+
+	m := r.Match
+	if len(m.Files)+len(m.AnyFiles)+len(m.Globs)+len(m.Dependencies)+len(m.PackageJSON)+len(m.Contents) == 0 && r.resolve == nil {
+		return fmt.Errorf("rule '%s' has no match conditions", r.Name)
+	}
+
+	var patterns []string
+	patterns = append(patterns, m.Globs...)
+	patterns = append(patterns, m.Dependencies...)
+	for _, content := range m.Contents {
+		if len(content.Files) == 0 {
+			return fmt.Errorf("rule '%s' has a contents condition without files", r.Name)
+		}
+		// An empty regex would match any existing file. Only package_json
+		// conditions use it, to check that a key exists.
+		if content.Regex == "" {
+			return fmt.Errorf("rule '%s' has a contents condition without regex", r.Name)
+		}
+		patterns = append(patterns, content.Files...)
+	}
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("rule '%s' has an invalid pattern '%s'", r.Name, pattern)
+		}
+	}
+
+	var expressions []string
+	for _, value := range m.PackageJSON {
+		if value.Path == "" {
+			return fmt.Errorf("rule '%s' has a package_json condition without path", r.Name)
+		}
+		expressions = append(expressions, value.Regex)
+	}
+	for _, content := range m.Contents {
+		expressions = append(expressions, content.Regex)
+	}
+	for _, expression := range expressions {
+		if _, err := regexp.Compile(expression); err != nil {
+			return fmt.Errorf("rule '%s' has an invalid regex: %w", r.Name, err)
+		}
+	}
+
+	for _, port := range r.Ports {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("rule '%s' has an invalid port %d", r.Name, port)
+		}
+	}
+
+	if r.Confidence < 1 || r.Confidence > MaxConfidence {
+		return fmt.Errorf("rule '%s' has a confidence of %d, not between 1 and %d", r.Name, r.Confidence, MaxConfidence)
+	}
+	return nil
+}
+
+// ServiceName returns the name of the service proposed by the rule.
+func (r *Rule) ServiceName() string {
+	switch {
+	case r.Service != "":
+		return r.Service
+	case r.Kind != "":
+		return string(r.Kind)
+	}
+	return r.Name
+}
+
+// Evaluate checks every condition of the rule against dir and, when all
+// match, builds the proposed service.
+func (r *Rule) Evaluate(dir string) RuleMatch {
+	match := RuleMatch{Rule: *r, Conditions: r.Match.evaluate(dir), Matched: true}
+	for _, condition := range match.Conditions {
+		match.Matched = match.Matched && condition.Matched
+	}
+	if !match.Matched {
+		return match
+	}
+
+	result := &DetectionResult{Command: r.Command, DetectionReason: r.Description}
+	if r.resolve != nil {
+		if result = r.resolve(dir); result == nil {
+			return match
+		}
+	}
+	if result.DetectionReason == "" {
+		result.DetectionReason = fmt.Sprintf("Matched rule %s", r.Name)
+	}
+	result.Name = r.ServiceName()
+	result.Kind = r.Kind
+	result.Confidence = r.Confidence
+	result.Ports = r.Ports
+	result.Env = r.Env
+	result.Rule = r.Name
+	match.Result = result
+	return match
+}
+
+// Describe returns the conditions of the match in words, in the order
+// they are evaluated.
+func (m Match) Describe() []string {
+	var descriptions []string
+	for _, file := range m.Files {
+		descriptions = append(descriptions, fmt.Sprintf("file %s exists", file))
+	}
+	if len(m.AnyFiles) > 0 {
+		descriptions = append(descriptions, fmt.Sprintf("one of %s exists", strings.Join(m.AnyFiles, ", ")))
+	}
+	for _, pattern := range m.Globs {
+		descriptions = append(descriptions, fmt.Sprintf("files match %s", pattern))
+	}
+	for _, dependency := range m.Dependencies {
+		descriptions = append(descriptions, fmt.Sprintf("package.json depends on %s", dependency))
+	}
+	for _, value := range m.PackageJSON {
+		if value.Regex != "" {
+			descriptions = append(descriptions, fmt.Sprintf("package.json %s matches /%s/", value.Path, value.Regex))
+		} else {
+			descriptions = append(descriptions, fmt.Sprintf("package.json has %s", value.Path))
+		}
+	}
+	for _, content := range m.Contents {
+		descriptions = append(descriptions, fmt.Sprintf("%s contains /%s/", strings.Join(content.Files, " or "), content.Regex))
+	}
+	return descriptions
+}
+
+func (m Match) evaluate(dir string) []Condition {
+	var matched []bool
+	for _, file := range m.Files {
+		matched = append(matched, fileExists(filepath.Join(dir, filepath.FromSlash(file))))
+	}
+
+	if len(m.AnyFiles) > 0 {
+		found := false
+		for _, file := range m.AnyFiles {
+			found = found || fileExists(filepath.Join(dir, filepath.FromSlash(file)))
+		}
+		matched = append(matched, found)
+	}
+
+	for _, pattern := range m.Globs {
+		matches, _ := filepath.Glob(filepath.Join(dir, filepath.FromSlash(pattern)))
+		matched = append(matched, len(matches) > 0)
+	}
+
+	if len(m.Dependencies) > 0 || len(m.PackageJSON) > 0 {
+		packageJSON := readPackageJSON(dir)
+		deps := extractDependencies(packageJSON)
+		for _, dependency := range m.Dependencies {
+			found := false
+			for _, dep := range deps {
+				if ok, _ := path.Match(dependency, dep); ok {
+					found = true
+				}
+			}
+			matched = append(matched, found)
+		}
+		for _, value := range m.PackageJSON {
+			matched = append(matched, value.matches(packageJSON))
+		}
+	}
+
+	for _, content := range m.Contents {
+		matched = append(matched, content.matches(dir))
+	}
+
+	conditions := make([]Condition, len(matched))
+	for i, description := range m.Describe() {
+		conditions[i] = Condition{Description: description, Matched: matched[i]}
+	}
+	return conditions
+}
+
+func (j JSONMatch) matches(packageJSON map[string]interface{}) bool {
+	var value interface{} = packageJSON
+	for _, key := range strings.Split(j.Path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if value, ok = object[key]; !ok {
+			return false
+		}
+	}
+
+	if j.Regex == "" {
+		return true
+	}
+	text, ok := value.(string)
+	if !ok {
+		data, _ := json.Marshal(value)
+		text = string(data)
+	}
+	return regexp.MustCompile(j.Regex).MatchString(text)
+}
+
+func (c ContentMatch) matches(dir string) bool {
+	expression := regexp.MustCompile(c.Regex)
+	for _, pattern := range c.Files {
+		files, _ := filepath.Glob(filepath.Join(dir, filepath.FromSlash(pattern)))
+		for _, file := range files {
+			if data, err := os.ReadFile(file); err == nil && expression.Match(data) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readPackageJSON returns the parsed package.json of dir, or nil.
+func readPackageJSON(dir string) map[string]interface{} {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var packageJSON map[string]interface{}
+	if json.Unmarshal(data, &packageJSON) != nil {
+		return nil
+	}
+	return packageJSON
+}
+
+// Rules returns the rules of the detector in precedence order.
+func (d *ServiceDetector) Rules() []Rule {
+	return d.rules
+}
+
+// Explain evaluates every rule against dir, in precedence order, and marks
+// the rules whose services DetectServices proposes.
+func (d *ServiceDetector) Explain(dir string) []RuleMatch {
+	matches := d.evaluate(dir, true)
+	for _, ranked := range rankMatches(matches) {
+		matches[ranked[0]].Selected = true
+		for _, i := range ranked[1:] {
+			matches[i].Preferred = matches[ranked[0]].Rule.Name
+		}
+	}
+	return matches
+}
+
+// detectRules returns the services proposed for dir, one per kind, from
+// the matching rule with the highest confidence. The services proposed by
+// the other matching rules of the kind are its alternatives, leaving out
+// those with the same command. Rules looking at the system are only
+// evaluated for the root of a scan.
+func (d *ServiceDetector) detectRules(dir string, root bool) []DetectionResult {
+	matches := d.evaluate(dir, root)
+
+	var results []DetectionResult
+	for _, ranked := range rankMatches(matches) {
+		result := *matches[ranked[0]].Result
+		commands := map[string]bool{result.Command: true}
+		for _, i := range ranked[1:] {
+			alternative := *matches[i].Result
+			if !commands[alternative.Command] {
+				commands[alternative.Command] = true
+				result.Alternatives = append(result.Alternatives, alternative)
+			}
+		}
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return kindOrder(results[i].Kind) < kindOrder(results[j].Kind)
+	})
+	return results
+}
+
+func (d *ServiceDetector) evaluate(dir string, root bool) []RuleMatch {
+	matches := make([]RuleMatch, len(d.rules))
+	for i := range d.rules {
+		if d.rules[i].rootOnly && !root {
+			matches[i] = RuleMatch{Rule: d.rules[i]}
+			continue
+		}
+		matches[i] = d.rules[i].Evaluate(dir)
+	}
+	return matches
+}
+
+// rankMatches groups the matches proposing a service by the slot of their
+// rule and orders each group by confidence, keeping precedence order
+// between rules of equal confidence.
+func rankMatches(matches []RuleMatch) [][]int {
+	var groups [][]int
+	slots := make(map[string]int)
+	for i, match := range matches {
+		if match.Result == nil {
+			continue
+		}
+		slot := match.Rule.slot()
+		if _, exists := slots[slot]; !exists {
+			slots[slot] = len(groups)
+			groups = append(groups, nil)
+		}
+		groups[slots[slot]] = append(groups[slots[slot]], i)
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group, func(a, b int) bool {
+			return matches[group[a]].Result.Confidence > matches[group[b]].Result.Confidence
+		})
+	}
+	return groups
+}
+
+// slot is what rules compete for: their kind, or the service name of
+// rules without one.
+func (r *Rule) slot() string {
+	if r.Kind != "" {
+		return string(r.Kind)
+	}
+	return r.ServiceName()
+}
+
+// kindOrder lists frontends, backends and databases before other kinds.
+func kindOrder(kind models.ServiceType) int {
+	switch kind {
+	case models.ServiceFrontend:
+		return 0
+	case models.ServiceBackend:
+		return 1
+	case models.ServiceDB:
+		return 2
+	}
+	return 3
+}
@@ -0,0 +1,256 @@
+package detector
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+func TestBuiltinRulesAreValid(t *testing.T) {
+	names := make(map[string]bool)
+	for _, rule := range BuiltinRules() {
+		if err := rule.Validate(); err != nil {
+			t.Error(err)
+		}
+		if names[rule.Name] {
+			t.Errorf("Rule %s is defined twice", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+}
+
+func TestLoadRulesPrecedence(t *testing.T) {
+	home := t.TempDir()
+	userDir := filepath.Join(home, RulesDir)
+	repo := t.TempDir()
+	writeFiles(t, home, map[string]string{
+		RulesDir + "/acme.yaml": `
+rules:
+  - name: acme
+    description: Detected Acme service
+    kind: backend
+    service: api
+    match:
+      files: [acme.toml]
+      contents:
+        - files: ["*.toml"]
+          regex: 'runtime\s*=\s*"acme"'
+    command: acme serve
+    ports: [8100]
+    env:
+      ACME_ENV: dev
+  - name: go
+    kind: backend
+    match:
+      files: [go.mod]
+    command: go run ./cmd/server
+`,
+	})
+	writeFiles(t, repo, map[string]string{
+		".git/HEAD": "",
+		".loex/detectors.d/local.yml": `
+rules:
+  - name: storybook
+    kind: storybook
+    match:
+      package_json:
+        - path: scripts.storybook
+          regex: '^storybook dev'
+    command: npm run storybook
+`,
+		"web/package.json":       `{"scripts": {"dev": "vite", "storybook": "storybook dev -p 6006"}, "dependencies": {"react": "18", "vite": "5"}}`,
+		"web/node_modules/.keep": "",
+		"web/acme.toml":          "runtime = \"acme\"\n",
+	})
+
+	// Repository rules are found from subdirectories of the repository.
+	rules, err := LoadRules(userDir, filepath.Join(repo, "web"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules[0].Name != "storybook" || rules[1].Name != "acme" || rules[2].Name != "go" || rules[2].Source != filepath.Join(userDir, "acme.yaml") {
+		t.Fatalf("Unexpected precedence: %s, %s, %s (%s)", rules[0].Name, rules[1].Name, rules[2].Name, rules[2].Source)
+	}
+	for _, rule := range rules[3:] {
+		if rule.Name == "go" {
+			t.Fatal("Expected the user rule to replace the built-in go rule")
+		}
+	}
+
+	results, err := NewWithRules(rules).DetectServices(filepath.Join(repo, "web"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string]DetectionResult)
+	for _, result := range results {
+		found[result.Name] = result
+	}
+	if len(found) != 3 || found["frontend"].Command != "npm run dev" || found["storybook"].Command != "npm run storybook" {
+		t.Fatalf("Unexpected results: %+v", results)
+	}
+	api := found["api"]
+	expected := DetectionResult{
+		Name:            "api",
+		Kind:            models.ServiceBackend,
+		Command:         "acme serve",
+		Ports:           []int{8100},
+		Env:             map[string]string{"ACME_ENV": "dev"},
+		Rule:            "acme",
+		Confidence:      MaxConfidence,
+		DetectionReason: "Detected Acme service",
+	}
+	if !reflect.DeepEqual(api, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, api)
+	}
+}
+
+func TestReadRulesFileErrors(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"rules:\n  - name: a\n    match: {files: [x]}\n", "has no command"},
+		{"rules:\n  - name: a\n    command: run\n", "has no match conditions"},
+		{"rules:\n  - name: a\n    command: run\n    match: {contents: [{files: [x], regex: '('}]}\n", "invalid regex"},
+		{"rules:\n  - name: a\n    command: run\n    match: {contents: [{files: [x]}]}\n", "contents condition without regex"},
+		{"rules:\n  - name: a\n    command: run\n    ports: [70000]\n    match: {files: [x]}\n", "invalid port"},
+		{"rules:\n  - name: a\n    command: run\n    match: {files: [x]}\n  - name: a\n    command: run\n    match: {files: [y]}\n", "defined twice"},
+	}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"rules.yaml": test.content})
+		_, err := ReadRulesFile(filepath.Join(dir, "rules.yaml"))
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("Expected error containing %q, got %v", test.expected, err)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"package.json":       `{"scripts": {"dev": "next dev"}, "dependencies": {"next": "14", "react": "18"}}`,
+		"node_modules/.keep": "",
+	})
+
+	matches := make(map[string]RuleMatch)
+	for _, match := range New().Explain(dir) {
+		matches[match.Rule.Name] = match
+	}
+
+	if next := matches["nextjs"]; !next.Selected || next.Result.Command != "npm run dev" {
+		t.Errorf("Expected nextjs to be selected, got %+v", next)
+	}
+	if react := matches["react"]; !react.Matched || react.Selected {
+		t.Errorf("Expected react to match without being selected, got %+v", react)
+	}
+	nuxt := matches["nuxt"]
+	expected := []Condition{{"file package.json exists", true}, {"package.json depends on nuxt", false}}
+	if nuxt.Matched || !reflect.DeepEqual(nuxt.Conditions, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, nuxt.Conditions)
+	}
+}
+
+func TestFrameworkPrecedence(t *testing.T) {
+	tests := []struct {
+		name         string
+		deps         string
+		rule         string
+		alternatives []string
+	}{
+		{"next over react", `"next": "14", "react": "18", "react-dom": "18"`, "nextjs", nil},
+		{"nuxt over vue", `"nuxt": "3", "vue": "3"`, "nuxt", nil},
+		{"sveltekit over vite", `"@sveltejs/kit": "2", "vite": "5"`, "sveltekit", nil},
+		{"react with vite over vite", `"react": "18", "vite": "5"`, "react-vite", nil},
+		{"create react app over react", `"react": "18", "react-scripts": "5"`, "create-react-app", nil},
+	}
+
+	// Reversing the rules shows precedence comes from confidence rather
+	// than from the order of the rules.
+	reversed := BuiltinRules()
+	slices.Reverse(reversed)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				"package.json":       `{"scripts": {"dev": "run-dev"}, "dependencies": {` + test.deps + `}}`,
+				"node_modules/.keep": "",
+			})
+
+			for _, serviceDetector := range []*ServiceDetector{New(), NewWithRules(reversed)} {
+				result := proposed(serviceDetector, dir, models.ServiceFrontend)
+				if result == nil || result.Rule != test.rule {
+					t.Fatalf("Expected rule %s, got %+v", test.rule, result)
+				}
+			}
+		})
+	}
+
+	confidence := make(map[string]int)
+	for _, rule := range BuiltinRules() {
+		confidence[rule.Name] = rule.Confidence
+	}
+	for _, pair := range [][2]string{{"nextjs", "react"}, {"nuxt", "vue"}, {"remix", "react"}, {"astro", "node"}, {"go-main", "go"}, {"maven-wrapper", "maven"}} {
+		if confidence[pair[0]] <= confidence[pair[1]] {
+			t.Errorf("Expected %s to rank over %s", pair[0], pair[1])
+		}
+	}
+}
+
+func TestAlternatives(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"package.json":       `{"scripts": {"build": "next build"}, "dependencies": {"next": "14", "react": "18", "react-scripts": "5"}}`,
+		"node_modules/.keep": "",
+		"go.mod":             "module example.com/app\n",
+		"main.go":            "package main\n",
+	})
+
+	results, err := New().DetectServices(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := make(map[string][]string)
+	for _, result := range results {
+		candidates := []string{fmt.Sprintf("%s %d", result.Command, result.Confidence)}
+		for _, alternative := range result.Alternatives {
+			candidates = append(candidates, fmt.Sprintf("%s %d", alternative.Command, alternative.Confidence))
+		}
+		found[result.Name] = candidates
+	}
+	expected := map[string][]string{
+		// The React rule proposes nothing without a dev script.
+		"frontend": {"npx next dev 90", "npx react-scripts start 70"},
+		// go run . is an alternative to go run main.go.
+		"backend": {"go run main.go 90", "go run . 80"},
+	}
+	if !reflect.DeepEqual(found, expected) {
+		t.Fatalf("Expected %v, got %v", expected, found)
+	}
+}
+
+func TestDatabaseHint(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"config/database.yml": "development:\n  adapter: postgresql\n",
+	})
+
+	d := New()
+	if hint := d.DatabaseHint(dir, nil); !strings.Contains(hint, "no database service found") {
+		t.Errorf("Expected a hint for a configured database, got %q", hint)
+	}
+	if hint := d.DatabaseHint(dir, []DetectionResult{{Name: "db", Kind: models.ServiceDB}}); hint != "" {
+		t.Errorf("Expected no hint when a database was detected, got %q", hint)
+	}
+	if hint := d.DatabaseHint(t.TempDir(), nil); hint != "" {
+		t.Errorf("Expected no hint without database configuration, got %q", hint)
+	}
+}
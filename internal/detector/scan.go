@@ -0,0 +1,239 @@
+package detector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+// DefaultScanDepth is the number of directory levels below the root
+// scanned by DetectRecursive.
+const DefaultScanDepth = 3
+
+// DefaultIgnore are the directories DetectRecursive never enters: hidden
+// directories, dependencies and build output.
+var DefaultIgnore = []string{".*", "node_modules", "vendor", "target", "dist"}
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// ScanOptions controls DetectRecursive. Ignore patterns are matched with
+// filepath.Match against the name of each directory and its path relative
+// to the root.
+type ScanOptions struct {
+	MaxDepth int
+	Ignore   []string
+}
+
+// appDir is a directory with detected services. Results may run from
+// another directory, e.g. Gradle subprojects run from the root.
+type appDir struct {
+	dir     string
+	results []DetectionResult
+}
+
+// DetectRecursive detects the services of a repository holding several
+// apps. Members of npm, yarn and pnpm workspaces, go.work, Cargo
+// workspaces and Gradle multi-projects are detected wherever they are;
+// other directories are scanned up to opts.MaxDepth levels. A directory
+// with detected services is an app and is not scanned further. Services
+// found below the root are named after their directory and run in it.
+func (d *ServiceDetector) DetectRecursive(root string, opts ScanOptions) ([]DetectionResult, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if !isDir(root) {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	var apps []appDir
+	visited := map[string]bool{root: true}
+
+	var rootResults []DetectionResult
+	if workspaces := FindWorkspaces(root); len(workspaces) > 0 && !hasMember(workspaces, root) {
+		// The root of a workspace holds tooling, not an app.
+		for _, result := range d.detectRules(root, true) {
+			if result.Kind == models.ServiceDB {
+				rootResults = append(rootResults, result)
+				break
+			}
+		}
+	} else if rootResults, err = d.DetectServices(root); err != nil {
+		return nil, err
+	}
+	apps = append(apps, appDir{dir: root, results: rootResults})
+
+	var scanErr error
+	var scan func(dir string, depth int)
+	scan = func(dir string, depth int) {
+		for _, workspace := range FindWorkspaces(dir) {
+			for _, member := range workspace.Members {
+				if visited[member] || ignored(root, member, opts.Ignore) {
+					continue
+				}
+				visited[member] = true
+
+				var results []DetectionResult
+				if workspace.Kind == WorkspaceGradle {
+					if result := detectGradleSubproject(dir, member, workspace.GradlePaths[member]); result != nil {
+						results = append(results, *result)
+					}
+				} else {
+					var err error
+					if results, err = d.detectApp(member); err != nil {
+						scanErr = err
+						return
+					}
+					for i := range results {
+						results[i].DetectionReason += fmt.Sprintf(" in %s workspace", workspace.Kind)
+					}
+				}
+				if len(results) > 0 {
+					apps = append(apps, appDir{dir: member, results: results})
+				} else {
+					scan(member, depth+1)
+				}
+				if scanErr != nil {
+					return
+				}
+			}
+		}
+
+		if depth >= opts.MaxDepth {
+			return
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			sub := filepath.Join(dir, entry.Name())
+			if !entry.IsDir() || visited[sub] || ignored(root, sub, opts.Ignore) {
+				continue
+			}
+			visited[sub] = true
+
+			results, err := d.detectApp(sub)
+			if err != nil {
+				scanErr = err
+				return
+			}
+			if len(results) > 0 {
+				apps = append(apps, appDir{dir: sub, results: results})
+				continue
+			}
+			scan(sub, depth+1)
+			if scanErr != nil {
+				return
+			}
+		}
+	}
+	scan(root, 0)
+	if scanErr != nil {
+		return nil, scanErr
+	}
+
+	return nameApps(root, apps), nil
+}
+
+// detectApp detects the services of a directory below the root. Unlike
+// DetectServices it skips rules looking at the system, such as databases
+// installed with Homebrew, which belong to the root.
+func (d *ServiceDetector) detectApp(dir string) ([]DetectionResult, error) {
+	if results, err := d.detectProcfile(dir); err != nil || len(results) > 0 {
+		return results, err
+	}
+
+	return d.detectRules(dir, false), nil
+}
+
+// detectGradleSubproject proposes running an application subproject
+// through the wrapper of the root project. Subprojects without the Spring
+// Boot or application plugin are libraries.
+func detectGradleSubproject(root, dir, projectPath string) *DetectionResult {
+	task := gradleTask(dir)
+	if task == "" {
+		return nil
+	}
+	return &DetectionResult{
+		Kind:            models.ServiceBackend,
+		Command:         fmt.Sprintf("%s %s:%s", gradleCommand(root), projectPath, task),
+		Dir:             root,
+		Confidence:      80,
+		DetectionReason: fmt.Sprintf("Detected Gradle subproject %s", projectPath),
+	}
+}
+
+func hasMember(workspaces []Workspace, dir string) bool {
+	for _, workspace := range workspaces {
+		for _, member := range workspace.Members {
+			if member == dir {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func ignored(root, dir string, patterns []string) bool {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, filepath.Base(dir)); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(pattern, rel); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// nameApps sets the directory and a unique name of every result. Services
+// of the root keep their names; an app below it is named after its
+// directory, suffixed with the service name when it has several services.
+// Clashing names use the path relative to the root instead.
+func nameApps(root string, apps []appDir) []DetectionResult {
+	sort.SliceStable(apps, func(i, j int) bool { return apps[i].dir < apps[j].dir })
+
+	var results []DetectionResult
+	used := make(map[string]bool)
+	for _, app := range apps {
+		rel, _ := filepath.Rel(root, app.dir)
+		for _, result := range app.results {
+			if result.Dir == "" {
+				result.Dir = app.dir
+			}
+
+			if rel != "." {
+				candidates := []string{filepath.Base(app.dir), strings.ReplaceAll(filepath.ToSlash(rel), "/", "-")}
+				for i, candidate := range candidates {
+					candidate = strings.Trim(unsafeNameChars.ReplaceAllString(candidate, "-"), "-")
+					if len(app.results) > 1 {
+						candidate += "-" + result.Name
+					}
+					candidates[i] = candidate
+				}
+				result.Name = candidates[0]
+				if used[result.Name] {
+					result.Name = candidates[1]
+				}
+			}
+			for base, n := result.Name, 2; used[result.Name]; n++ {
+				result.Name = fmt.Sprintf("%s-%d", base, n)
+			}
+
+			used[result.Name] = true
+			results = append(results, result)
+		}
+	}
+	return results
+}
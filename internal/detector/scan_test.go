@@ -0,0 +1,129 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+// proposed returns the service of kind that detectRules proposes for dir.
+func proposed(d *ServiceDetector, dir string, kind models.ServiceType) *DetectionResult {
+	for _, result := range d.detectRules(dir, true) {
+		if result.Kind == kind {
+			return &result
+		}
+	}
+	return nil
+}
+
+// writeFiles creates a fixture tree below root from paths and contents.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		path = filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDetectRecursiveMonorepo(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"package.json":                       `{"private": true, "workspaces": ["apps/*", "packages/*"], "scripts": {"dev": "turbo dev"}}`,
+		"apps/web/package.json":              `{"scripts": {"dev": "next dev"}, "dependencies": {"next": "14.0.0"}}`,
+		"apps/admin/package.json":            `{"scripts": {"start": "react-scripts start"}, "dependencies": {"react": "18.0.0"}}`,
+		"packages/config/package.json":       `{"name": "config"}`,
+		"services/api/go.mod":                "module example.com/api\n",
+		"services/api/main.go":               "package main\n",
+		"services/worker/Cargo.toml":         "[package]\nname = \"worker\"\n",
+		"node_modules/left-pad/package.json": `{"scripts": {"start": "node ."}}`,
+		"tools/scripts/deep/api/go.mod":      "module example.com/deep\n",
+	})
+
+	results, err := New().DetectRecursive(root, ScanOptions{MaxDepth: 2, Ignore: DefaultIgnore})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := make(map[string]string)
+	for _, result := range results {
+		rel, _ := filepath.Rel(root, result.Dir)
+		found[result.Name] = filepath.ToSlash(rel) + ": " + result.Command
+	}
+	expected := map[string]string{
+		"admin":  "apps/admin: npm start",
+		"web":    "apps/web: npm run dev",
+		"api":    "services/api: go run main.go",
+		"worker": "services/worker: cargo run",
+	}
+	if !reflect.DeepEqual(found, expected) {
+		t.Fatalf("Expected %v, got %v", expected, found)
+	}
+}
+
+func TestDetectRecursiveNamesAndWorkspaces(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.work":                          "go 1.22\n\nuse (\n\t./backend/api // public API\n\t./internal/tools/api\n)\n",
+		"backend/api/go.mod":               "module example.com/api\n",
+		"internal/tools/api/go.mod":        "module example.com/tools\n",
+		"settings.gradle.kts":              "rootProject.name = \"shop\"\ninclude(\":services:orders\", \":libs:common\")\n",
+		"gradlew":                          "#!/bin/sh\n",
+		"services/orders/build.gradle.kts": "plugins { id(\"org.springframework.boot\") }\n",
+		"libs/common/build.gradle.kts":     "plugins { `java-library` }\n",
+	})
+
+	// go.work members are found below the scan depth.
+	results, err := New().DetectRecursive(root, ScanOptions{MaxDepth: 1, Ignore: DefaultIgnore})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := make(map[string]string)
+	for _, result := range results {
+		rel, _ := filepath.Rel(root, result.Dir)
+		found[result.Name] = filepath.ToSlash(rel) + ": " + result.Command
+	}
+	expected := map[string]string{
+		"api":                "backend/api: go run .",
+		"internal-tools-api": "internal/tools/api: go run .",
+		"orders":             ".: ./gradlew :services:orders:bootRun",
+	}
+	if !reflect.DeepEqual(found, expected) {
+		t.Fatalf("Expected %v, got %v", expected, found)
+	}
+}
+
+func TestFindCargoAndPNPMWorkspaces(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"Cargo.toml":               "[workspace]\nmembers = [\n  \"crates/*\",\n]\nexclude = [\"crates/legacy\"]\n\n[workspace.dependencies]\nserde = \"1\"\n",
+		"crates/server/Cargo.toml": "[package]\nname = \"server\"\n",
+		"crates/legacy/Cargo.toml": "[package]\nname = \"legacy\"\n",
+		"pnpm-workspace.yaml":      "packages:\n  - 'apps/**'\n  - '!apps/docs'\n",
+		"apps/site/package.json":   "{}",
+		"apps/docs/package.json":   "{}",
+	})
+
+	members := make(map[string][]string)
+	for _, workspace := range FindWorkspaces(root) {
+		for _, member := range workspace.Members {
+			rel, _ := filepath.Rel(root, member)
+			members[workspace.Kind] = append(members[workspace.Kind], filepath.ToSlash(rel))
+		}
+	}
+	expected := map[string][]string{
+		WorkspaceCargo: {"crates/server"},
+		WorkspacePNPM:  {"apps/site"},
+	}
+	if !reflect.DeepEqual(members, expected) {
+		t.Fatalf("Expected %v, got %v", expected, members)
+	}
+}
@@ -0,0 +1,225 @@
+package detector
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Workspace kinds.
+const (
+	WorkspaceNPM    = "npm"
+	WorkspaceYarn   = "yarn"
+	WorkspacePNPM   = "pnpm"
+	WorkspaceGo     = "go"
+	WorkspaceCargo  = "cargo"
+	WorkspaceGradle = "gradle"
+)
+
+// Workspace is a set of packages declared by a monorepo tool in a
+// directory. Members are absolute directories; for Gradle, GradlePaths
+// holds the project path of each member, e.g. ":services:api".
+type Workspace struct {
+	Kind        string
+	File        string
+	Members     []string
+	GradlePaths map[string]string
+}
+
+var (
+	cargoWorkspace = regexp.MustCompile(`(?s)\[workspace\](.*?)(?:\n\[|$)`)
+	cargoList      = regexp.MustCompile(`(?s)(members|exclude)\s*=\s*\[(.*?)\]`)
+	gradleInclude  = regexp.MustCompile(`(?m)^\s*include\b(.*)$`)
+	quoted         = regexp.MustCompile(`["']([^"']+)["']`)
+)
+
+// FindWorkspaces returns the workspaces declared in dir by npm, yarn,
+// pnpm, Go, Cargo and Gradle.
+func FindWorkspaces(dir string) []Workspace {
+	var workspaces []Workspace
+	for _, find := range []func(string) *Workspace{
+		findNodeWorkspace, findPNPMWorkspace, findGoWorkspace, findCargoWorkspace, findGradleWorkspace,
+	} {
+		if workspace := find(dir); workspace != nil && len(workspace.Members) > 0 {
+			workspaces = append(workspaces, *workspace)
+		}
+	}
+	return workspaces
+}
+
+// findNodeWorkspace reads the workspaces field of package.json, either a
+// list of patterns or an object with a packages list.
+func findNodeWorkspace(dir string) *Workspace {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+
+	var packageJSON struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if json.Unmarshal(data, &packageJSON) != nil || packageJSON.Workspaces == nil {
+		return nil
+	}
+
+	var patterns []string
+	if json.Unmarshal(packageJSON.Workspaces, &patterns) != nil {
+		var object struct {
+			Packages []string `json:"packages"`
+		}
+		if json.Unmarshal(packageJSON.Workspaces, &object) != nil {
+			return nil
+		}
+		patterns = object.Packages
+	}
+
+	kind := WorkspaceNPM
+	if fileExists(filepath.Join(dir, "yarn.lock")) {
+		kind = WorkspaceYarn
+	}
+	return &Workspace{Kind: kind, File: "package.json", Members: expandMembers(dir, patterns)}
+}
+
+func findPNPMWorkspace(dir string) *Workspace {
+	data, err := os.ReadFile(filepath.Join(dir, "pnpm-workspace.yaml"))
+	if err != nil {
+		return nil
+	}
+
+	var config struct {
+		Packages []string `yaml:"packages"`
+	}
+	if yaml.Unmarshal(data, &config) != nil {
+		return nil
+	}
+	return &Workspace{Kind: WorkspacePNPM, File: "pnpm-workspace.yaml", Members: expandMembers(dir, config.Packages)}
+}
+
+// findGoWorkspace reads the use directives of go.work, in their single
+// line and block forms.
+func findGoWorkspace(dir string) *Workspace {
+	file, err := os.Open(filepath.Join(dir, "go.work"))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var patterns []string
+	inUse := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case inUse && fields[0] == ")":
+			inUse = false
+		case inUse:
+			patterns = append(patterns, strings.Trim(fields[0], `"`))
+		case fields[0] == "use" && len(fields) > 1 && fields[1] == "(":
+			inUse = true
+		case fields[0] == "use" && len(fields) > 1:
+			patterns = append(patterns, strings.Trim(fields[1], `"`))
+		}
+	}
+	return &Workspace{Kind: WorkspaceGo, File: "go.work", Members: expandMembers(dir, patterns)}
+}
+
+// findCargoWorkspace reads the members and exclude lists of the
+// [workspace] table of Cargo.toml.
+func findCargoWorkspace(dir string) *Workspace {
+	data, err := os.ReadFile(filepath.Join(dir, "Cargo.toml"))
+	if err != nil {
+		return nil
+	}
+
+	section := cargoWorkspace.FindSubmatch(data)
+	if section == nil {
+		return nil
+	}
+
+	var patterns []string
+	for _, list := range cargoList.FindAllSubmatch(section[1], -1) {
+		for _, match := range quoted.FindAllSubmatch(list[2], -1) {
+			pattern := string(match[1])
+			if string(list[1]) == "exclude" {
+				pattern = "!" + pattern
+			}
+			patterns = append(patterns, pattern)
+		}
+	}
+	return &Workspace{Kind: WorkspaceCargo, File: "Cargo.toml", Members: expandMembers(dir, patterns)}
+}
+
+// findGradleWorkspace reads the include statements of the Gradle settings
+// file. A project path such as ":services:api" lives in services/api.
+func findGradleWorkspace(dir string) *Workspace {
+	for _, name := range []string{"settings.gradle", "settings.gradle.kts"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+
+		workspace := &Workspace{Kind: WorkspaceGradle, File: name, GradlePaths: make(map[string]string)}
+		for _, include := range gradleInclude.FindAllSubmatch(data, -1) {
+			for _, match := range quoted.FindAllSubmatch(include[1], -1) {
+				projectPath := ":" + strings.TrimPrefix(string(match[1]), ":")
+				member := filepath.Join(dir, filepath.FromSlash(strings.ReplaceAll(projectPath[1:], ":", "/")))
+				if isDir(member) && workspace.GradlePaths[member] == "" {
+					workspace.Members = append(workspace.Members, member)
+					workspace.GradlePaths[member] = projectPath
+				}
+			}
+		}
+		return workspace
+	}
+	return nil
+}
+
+// expandMembers resolves workspace patterns relative to dir. Patterns
+// starting with ! exclude directories; ** is treated as a single level.
+func expandMembers(dir string, patterns []string) []string {
+	included := make(map[string]bool)
+	excluded := make(map[string]bool)
+	for _, pattern := range patterns {
+		target := included
+		if strings.HasPrefix(pattern, "!") {
+			target = excluded
+			pattern = pattern[1:]
+		}
+		pattern = strings.ReplaceAll(filepath.FromSlash(strings.TrimSuffix(pattern, "/")), "**", "*")
+
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			if isDir(match) {
+				target[filepath.Clean(match)] = true
+			}
+		}
+	}
+
+	var members []string
+	for member := range included {
+		if !excluded[member] {
+			members = append(members, member)
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,144 @@
+package env
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Var is a variable definition. Literal values are not interpolated, as
+// for single quoted values in dotenv files.
+type Var struct {
+	Key     string
+	Value   string
+	Literal bool
+}
+
+// LoadDotenv reads variables from a dotenv file.
+func LoadDotenv(path string) ([]Var, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	vars, err := ParseDotenv(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return vars, nil
+}
+
+// ParseDotenv parses KEY=value lines. Blank lines, comments and a leading
+// "export" are ignored. Values may be single quoted (literal), double
+// quoted (with \n, \t, \" and \\ escapes, possibly spanning lines) or
+// unquoted with an optional trailing " # comment".
+func ParseDotenv(r io.Reader) ([]Var, error) {
+	var vars []Var
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !ValidName(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=value", lineNumber)
+		}
+		value = strings.TrimSpace(value)
+
+		switch {
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated single quote", lineNumber)
+			}
+			vars = append(vars, Var{Key: key, Value: value[1 : end+1], Literal: true})
+
+		case strings.HasPrefix(value, `"`):
+			text := value[1:]
+			for !hasClosingQuote(text) {
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("line %d: unterminated double quote", lineNumber)
+				}
+				lineNumber++
+				text += "\n" + scanner.Text()
+			}
+			vars = append(vars, Var{Key: key, Value: unescape(text[:closingQuote(text)])})
+
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+			vars = append(vars, Var{Key: key, Value: value})
+		}
+	}
+
+	return vars, scanner.Err()
+}
+
+// ValidName reports whether name can be used as a variable name.
+func ValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		if c != '_' && (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func closingQuote(text string) int {
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func hasClosingQuote(text string) bool {
+	return closingQuote(text) >= 0
+}
+
+func unescape(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' || i+1 == len(text) {
+			b.WriteByte(text[i])
+			continue
+		}
+		i++
+		switch text[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '"', '\\':
+			b.WriteByte(text[i])
+		case '$':
+			// Keep the escape so that interpolation leaves it alone.
+			b.WriteString(`\$`)
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(text[i])
+		}
+	}
+	return b.String()
+}
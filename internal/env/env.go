@@ -0,0 +1,273 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+// SourceInherited marks variables taken from the environment of loex.
+const SourceInherited = "inherited"
+
+// SourcePorts marks the named ports of a service and its dependencies.
+const SourcePorts = "named ports"
+
+var secretPattern = regexp.MustCompile(`(?i)(SECRET|PASSWORD|PASSWD|TOKEN|API_?KEY|PRIVATE|CREDENTIAL|AUTH|_KEY$|^KEY$|DSN$|DATABASE_URL)`)
+
+// Layer is a set of variables from one source, such as a dotenv file or
+// an env map.
+type Layer struct {
+	Source string
+	Vars   []Var
+}
+
+// Env is a resolved environment together with the source of every
+// variable.
+type Env struct {
+	Vars    map[string]string
+	Sources map[string]string
+}
+
+// ForService resolves the environment of a service. Later sources take
+// precedence: the environment of loex, the project env files, the project
+// env, the service env files and finally the service env. Ports, the named
+// ports visible to the service, override all sources; they are set first
+// so other variables can reference them.
+func ForService(project *models.Project, service models.Service, ports map[string]int) (*Env, error) {
+	layers, err := Layers(project, service)
+	if err != nil {
+		return nil, err
+	}
+	if len(ports) > 0 {
+		vars := make(map[string]string, len(ports))
+		for name, port := range ports {
+			vars[name] = strconv.Itoa(port)
+		}
+		for i := range layers {
+			layers[i].Vars = slices.DeleteFunc(layers[i].Vars, func(v Var) bool {
+				_, isPort := vars[v.Key]
+				return isPort
+			})
+		}
+		layers = append([]Layer{{Source: SourcePorts, Vars: mapVars(vars)}}, layers...)
+	}
+	return Resolve(os.Environ(), layers), nil
+}
+
+// Layers returns the variable sources of a service in order of precedence.
+// Env files are relative to the service directory.
+func Layers(project *models.Project, service models.Service) ([]Layer, error) {
+	var layers []Layer
+
+	addFiles := func(files []string) error {
+		for _, file := range files {
+			path := file
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(service.Dir, path)
+			}
+			vars, err := LoadDotenv(path)
+			if err != nil {
+				return fmt.Errorf("failed to load env file: %w", err)
+			}
+			layers = append(layers, Layer{Source: file, Vars: vars})
+		}
+		return nil
+	}
+
+	if err := addFiles(project.EnvFiles); err != nil {
+		return nil, err
+	}
+	layers = append(layers, Layer{Source: "project env", Vars: mapVars(project.Env)})
+	if err := addFiles(service.EnvFiles); err != nil {
+		return nil, err
+	}
+	layers = append(layers, Layer{Source: "service env", Vars: mapVars(service.Env)})
+
+	return layers, nil
+}
+
+// Resolve applies the layers over the base environment, given as
+// KEY=value strings. Values may reference other variables as $VAR or
+// ${VAR}, including variables of the same layer; ${VAR:-default} and
+// ${VAR-default} are supported as well.
+func Resolve(base []string, layers []Layer) *Env {
+	env := &Env{Vars: make(map[string]string), Sources: make(map[string]string)}
+	for _, kv := range base {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env.Vars[key] = value
+			env.Sources[key] = SourceInherited
+		}
+	}
+
+	for _, layer := range layers {
+		env.apply(layer)
+	}
+	return env
+}
+
+func (e *Env) apply(layer Layer) {
+	defined := make(map[string]Var)
+	for _, v := range layer.Vars {
+		defined[v.Key] = v
+	}
+
+	done := make(map[string]string)
+	active := make(map[string]bool)
+
+	var lookup func(key string) (string, bool)
+	lookup = func(key string) (string, bool) {
+		if value, ok := done[key]; ok {
+			return value, true
+		}
+		v, ok := defined[key]
+		if !ok || active[key] {
+			// Self references and cycles see the value of earlier sources.
+			value, ok := e.Vars[key]
+			return value, ok
+		}
+		if v.Literal {
+			done[key] = v.Value
+			return v.Value, true
+		}
+
+		active[key] = true
+		value := Expand(v.Value, lookup)
+		active[key] = false
+		done[key] = value
+		return value, true
+	}
+
+	for _, v := range layer.Vars {
+		value, _ := lookup(v.Key)
+		e.Vars[v.Key] = value
+		e.Sources[v.Key] = layer.Source
+	}
+}
+
+// Environ returns the environment as sorted KEY=value strings.
+func (e *Env) Environ() []string {
+	result := make([]string, 0, len(e.Vars))
+	for _, key := range e.Keys() {
+		result = append(result, key+"="+e.Vars[key])
+	}
+	return result
+}
+
+// Keys returns the variable names in sorted order.
+func (e *Env) Keys() []string {
+	keys := make([]string, 0, len(e.Vars))
+	for key := range e.Vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Expand replaces $VAR, ${VAR}, ${VAR:-default} and ${VAR-default} in s
+// using lookup. A backslash before $ keeps it literal.
+func Expand(s string, lookup func(string) (string, bool)) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == '$':
+			b.WriteByte('$')
+			i++
+
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			end := closingBrace(s[i:])
+			if end < 0 {
+				b.WriteString(s[i:])
+				return b.String()
+			}
+			b.WriteString(expandBraced(s[i+2:i+end], lookup))
+			i += end
+
+		case s[i] == '$':
+			j := i + 1
+			for j < len(s) && (s[j] == '_' || s[j] >= 'A' && s[j] <= 'Z' || s[j] >= 'a' && s[j] <= 'z' || j > i+1 && s[j] >= '0' && s[j] <= '9') {
+				j++
+			}
+			if j == i+1 {
+				b.WriteByte('$')
+				continue
+			}
+			value, _ := lookup(s[i+1 : j])
+			b.WriteString(value)
+			i = j - 1
+
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// closingBrace returns the index of the brace closing the ${ at the start
+// of s, allowing nested ${...} in defaults.
+func closingBrace(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func expandBraced(expr string, lookup func(string) (string, bool)) string {
+	if i := strings.Index(expr, ":-"); i >= 0 {
+		if value, ok := lookup(expr[:i]); ok && value != "" {
+			return value
+		}
+		return Expand(expr[i+2:], lookup)
+	}
+	if i := strings.IndexByte(expr, '-'); i >= 0 {
+		if value, ok := lookup(expr[:i]); ok {
+			return value
+		}
+		return Expand(expr[i+1:], lookup)
+	}
+	value, _ := lookup(expr)
+	return value
+}
+
+// IsSecret reports whether a variable name looks like it holds a secret.
+func IsSecret(name string) bool {
+	return secretPattern.MatchString(name)
+}
+
+// Mask hides the value of secret variables.
+func Mask(name, value string) string {
+	if !IsSecret(name) || value == "" {
+		return value
+	}
+	return "********"
+}
+
+func mapVars(values map[string]string) []Var {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	vars := make([]Var, 0, len(keys))
+	for _, key := range keys {
+		vars = append(vars, Var{Key: key, Value: values[key]})
+	}
+	return vars
+}
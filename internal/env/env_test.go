@@ -0,0 +1,140 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+func TestParseDotenv(t *testing.T) {
+	input := `# comment
+export HOST=localhost
+PORT=5432 # trailing comment
+PASSWORD='p@ss $word'
+GREETING="hello\nworld"
+MULTI="line one
+line two"
+`
+	vars, err := ParseDotenv(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Var{
+		{Key: "HOST", Value: "localhost"},
+		{Key: "PORT", Value: "5432"},
+		{Key: "PASSWORD", Value: "p@ss $word", Literal: true},
+		{Key: "GREETING", Value: "hello\nworld"},
+		{Key: "MULTI", Value: "line one\nline two"},
+	}
+	if len(vars) != len(expected) {
+		t.Fatalf("Expected %d vars, got %+v", len(expected), vars)
+	}
+	for i := range expected {
+		if vars[i] != expected[i] {
+			t.Errorf("Var %d = %+v, expected %+v", i, vars[i], expected[i])
+		}
+	}
+
+	if _, err := ParseDotenv(strings.NewReader("not a variable")); err == nil {
+		t.Error("Expected error for line without =")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	base := []string{"HOME=/home/dev", "PATH=/usr/bin"}
+	layers := []Layer{
+		{Source: ".env", Vars: []Var{
+			{Key: "DB_HOST", Value: "localhost"},
+			{Key: "DB_URL", Value: "postgres://${DB_HOST}:${DB_PORT:-5432}/app"},
+			{Key: "LITERAL", Value: "${DB_HOST}", Literal: true},
+		}},
+		{Source: "service env", Vars: []Var{
+			{Key: "PATH", Value: "$HOME/bin:${PATH}"},
+			{Key: "DB_HOST", Value: "db.internal"},
+			{Key: "A", Value: "${B}"},
+			{Key: "B", Value: "b"},
+		}},
+	}
+
+	env := Resolve(base, layers)
+	tests := map[string]string{
+		"DB_URL":  "postgres://localhost:5432/app",
+		"DB_HOST": "db.internal",
+		"LITERAL": "${DB_HOST}",
+		"PATH":    "/home/dev/bin:/usr/bin",
+		"A":       "b",
+	}
+	for key, expected := range tests {
+		if env.Vars[key] != expected {
+			t.Errorf("%s = %q, expected %q", key, env.Vars[key], expected)
+		}
+	}
+	if env.Sources["DB_HOST"] != "service env" || env.Sources["HOME"] != SourceInherited {
+		t.Errorf("Unexpected sources: %v", env.Sources)
+	}
+}
+
+func TestForServiceEnvFiles(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ".env"), []byte("PORT=3000\nNAME=base\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".env.local"), []byte("PORT=4000\n"), 0644)
+
+	project := &models.Project{
+		EnvFiles: []string{".env"},
+		Env:      map[string]string{"NAME": "project"},
+	}
+	service := models.Service{
+		Dir:      dir,
+		EnvFiles: []string{".env.local"},
+		Env:      map[string]string{"URL": "http://localhost:${PORT}"},
+	}
+
+	env, err := ForService(project, service, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.Vars["NAME"] != "project" || env.Vars["PORT"] != "4000" || env.Vars["URL"] != "http://localhost:4000" {
+		t.Errorf("Unexpected environment: NAME=%s PORT=%s URL=%s", env.Vars["NAME"], env.Vars["PORT"], env.Vars["URL"])
+	}
+
+	service.EnvFiles = []string{"missing.env"}
+	if _, err := ForService(project, service, nil); err == nil {
+		t.Error("Expected error for missing env file")
+	}
+}
+
+func TestForServicePorts(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ".env"), []byte("PORT=3000\n"), 0644)
+
+	project := &models.Project{}
+	service := models.Service{
+		Dir:      dir,
+		EnvFiles: []string{".env"},
+		Env:      map[string]string{"API_URL": "http://localhost:${API_PORT}/", "SELF": "localhost:$PORT"},
+	}
+
+	env, err := ForService(project, service, map[string]int{"PORT": 40001, "API_PORT": 40002})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.Vars["PORT"] != "40001" || env.Sources["PORT"] != SourcePorts {
+		t.Errorf("Expected the named port to override the env file, got PORT=%s from %s", env.Vars["PORT"], env.Sources["PORT"])
+	}
+	if env.Vars["API_URL"] != "http://localhost:40002/" || env.Vars["SELF"] != "localhost:40001" {
+		t.Errorf("Unexpected references: API_URL=%s SELF=%s", env.Vars["API_URL"], env.Vars["SELF"])
+	}
+}
+
+func TestMask(t *testing.T) {
+	if Mask("DB_PASSWORD", "hunter2") != "********" || Mask("GITHUB_TOKEN", "x") != "********" {
+		t.Error("Expected secrets to be masked")
+	}
+	if Mask("PORT", "3000") != "3000" {
+		t.Error("Expected PORT not to be masked")
+	}
+}
@@ -0,0 +1,240 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kjunh972/loex/internal/env"
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+const (
+	DefaultTimeout    = 5 * time.Second
+	DefaultInterval   = 1 * time.Second
+	DefaultRetries    = 60
+	DefaultHTTPStatus = http.StatusOK
+)
+
+// Target carries what a probe needs to know about the running service.
+// Env is the environment the service was started with: command probes run
+// with it and ${VAR} references in TCP and HTTP addresses are expanded from
+// it, so probes can reach auto allocated ports.
+type Target struct {
+	Dir       string
+	Env       *env.Env
+	LogPath   string
+	LogOffset int64
+}
+
+// expand replaces variable references in s with values from the service
+// environment. Without an environment s is returned unchanged.
+func (t Target) expand(s string) string {
+	if t.Env == nil {
+		return s
+	}
+	return env.Expand(s, func(name string) (string, bool) {
+		value, ok := t.Env.Vars[name]
+		return value, ok
+	})
+}
+
+// Timeout returns the per-attempt timeout of a health check.
+func Timeout(hc *models.Healthcheck) time.Duration {
+	if hc.Timeout > 0 {
+		return time.Duration(hc.Timeout)
+	}
+	return DefaultTimeout
+}
+
+// Interval returns the delay between two attempts of a health check.
+func Interval(hc *models.Healthcheck) time.Duration {
+	if hc.Interval > 0 {
+		return time.Duration(hc.Interval)
+	}
+	return DefaultInterval
+}
+
+// Retries returns how many failed attempts are allowed before the service
+// is considered unhealthy.
+func Retries(hc *models.Healthcheck) int {
+	if hc.Retries > 0 {
+		return hc.Retries
+	}
+	return DefaultRetries
+}
+
+// StartupWindow is how long a service may take to become ready before it is
+// reported as unhealthy.
+func StartupWindow(hc *models.Healthcheck) time.Duration {
+	return time.Duration(Retries(hc)) * (Interval(hc) + Timeout(hc))
+}
+
+// Describe returns a short human readable description of the probe.
+func Describe(hc *models.Healthcheck) string {
+	switch {
+	case hc.TCP != "":
+		return "tcp " + tcpAddress(hc.TCP)
+	case hc.HTTP != "":
+		return fmt.Sprintf("http %s (expect %d)", hc.HTTP, httpStatus(hc))
+	case hc.Command != "":
+		return "command " + hc.Command
+	case hc.LogPattern != "":
+		return "log /" + hc.LogPattern + "/"
+	}
+	return "none"
+}
+
+// Check runs a single attempt of the health check.
+func Check(hc *models.Healthcheck, target Target) error {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout(hc))
+	defer cancel()
+
+	switch {
+	case hc.TCP != "":
+		return checkTCP(ctx, target.expand(hc.TCP))
+	case hc.HTTP != "":
+		return checkHTTP(ctx, target.expand(hc.HTTP), httpStatus(hc))
+	case hc.Command != "":
+		return checkCommand(ctx, hc.Command, target)
+	case hc.LogPattern != "":
+		return checkLog(hc.LogPattern, target)
+	}
+	return fmt.Errorf("health check has no probe configured")
+}
+
+// Wait retries the health check until it succeeds, the retries are
+// exhausted or alive reports that the process has exited.
+func Wait(hc *models.Healthcheck, target Target, alive func() bool) error {
+	retries := Retries(hc)
+	interval := Interval(hc)
+
+	var lastErr error
+	for attempt := 0; attempt < retries; attempt++ {
+		if alive != nil && !alive() {
+			return fmt.Errorf("process exited before becoming ready")
+		}
+		if lastErr = Check(hc, target); lastErr == nil {
+			return nil
+		}
+		time.Sleep(interval)
+	}
+	return fmt.Errorf("not ready after %d attempts: %v", retries, lastErr)
+}
+
+// ParseSpec builds a health check from a compact command line form:
+//
+//	tcp:8080
+//	http://localhost:3000/health
+//	cmd:pg_isready -h localhost
+//	log:Listening on
+func ParseSpec(spec string) (*models.Healthcheck, error) {
+	switch {
+	case strings.HasPrefix(spec, "tcp:"):
+		return &models.Healthcheck{TCP: strings.TrimPrefix(spec, "tcp:")}, nil
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return &models.Healthcheck{HTTP: spec}, nil
+	case strings.HasPrefix(spec, "cmd:"):
+		return &models.Healthcheck{Command: strings.TrimPrefix(spec, "cmd:")}, nil
+	case strings.HasPrefix(spec, "log:"):
+		pattern := strings.TrimPrefix(spec, "log:")
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid log pattern: %w", err)
+		}
+		return &models.Healthcheck{LogPattern: pattern}, nil
+	}
+	return nil, fmt.Errorf("unknown health check '%s'. Use tcp:PORT, http://URL, cmd:COMMAND or log:REGEX", spec)
+}
+
+func httpStatus(hc *models.Healthcheck) int {
+	if hc.HTTPStatus > 0 {
+		return hc.HTTPStatus
+	}
+	return DefaultHTTPStatus
+}
+
+func tcpAddress(address string) string {
+	if _, err := strconv.Atoi(address); err == nil {
+		return net.JoinHostPort("127.0.0.1", address)
+	}
+	return address
+}
+
+func checkTCP(ctx context.Context, address string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", tcpAddress(address))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func checkHTTP(ctx context.Context, url string, expected int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != expected {
+		return fmt.Errorf("expected status %d, got %d", expected, resp.StatusCode)
+	}
+	return nil
+}
+
+func checkCommand(ctx context.Context, command string, target Target) error {
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	cmd.Dir = target.Dir
+	if target.Env != nil {
+		cmd.Env = target.Env.Environ()
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func checkLog(pattern string, target Target) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid log pattern: %w", err)
+	}
+
+	file, err := os.Open(target.LogPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	offset := target.LogOffset
+	if info, err := file.Stat(); err == nil && info.Size() < offset {
+		// The log was truncated or rotated since the service started.
+		offset = 0
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	if !re.Match(data) {
+		return fmt.Errorf("pattern /%s/ not found in log", pattern)
+	}
+	return nil
+}
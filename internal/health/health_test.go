@@ -0,0 +1,95 @@
+package health
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kjunh972/loex/internal/env"
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected models.Healthcheck
+	}{
+		{"tcp:8080", models.Healthcheck{TCP: "8080"}},
+		{"http://localhost:3000/health", models.Healthcheck{HTTP: "http://localhost:3000/health"}},
+		{"cmd:pg_isready -h localhost", models.Healthcheck{Command: "pg_isready -h localhost"}},
+		{"log:Listening on \\d+", models.Healthcheck{LogPattern: "Listening on \\d+"}},
+	}
+
+	for _, tt := range tests {
+		hc, err := ParseSpec(tt.spec)
+		if err != nil {
+			t.Errorf("ParseSpec(%q) failed: %v", tt.spec, err)
+			continue
+		}
+		if *hc != tt.expected {
+			t.Errorf("ParseSpec(%q) = %+v, expected %+v", tt.spec, *hc, tt.expected)
+		}
+	}
+
+	if _, err := ParseSpec("ping:localhost"); err == nil {
+		t.Error("Expected error for unknown probe type")
+	}
+}
+
+func TestCheckTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	address := listener.Addr().String()
+
+	hc := &models.Healthcheck{TCP: address}
+	if err := Check(hc, Target{}); err != nil {
+		t.Errorf("Expected open port to pass, got: %v", err)
+	}
+
+	listener.Close()
+	if err := Check(hc, Target{}); err == nil {
+		t.Error("Expected closed port to fail")
+	}
+}
+
+func TestCheckUsesServiceEnv(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	target := Target{Env: &env.Env{Vars: map[string]string{"PORT": port}}}
+	if err := Check(&models.Healthcheck{TCP: "${PORT}"}, target); err != nil {
+		t.Errorf("Expected ${PORT} to expand to the listening port, got: %v", err)
+	}
+	if err := Check(&models.Healthcheck{Command: `test "$PORT" = ` + port}, target); err != nil {
+		t.Errorf("Expected the command to see PORT=%s, got: %v", port, err)
+	}
+	if err := Check(&models.Healthcheck{Command: `test -n "$PORT"`}, Target{Env: &env.Env{}}); err == nil {
+		t.Error("Expected the command to run with the service environment only")
+	}
+}
+
+func TestCheckLogOnlyReadsNewOutput(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "api.log")
+	old := "server ready\n"
+	if err := os.WriteFile(logPath, []byte(old+"booting\n"), 0644); err != nil {
+		t.Fatalf("Failed to write log: %v", err)
+	}
+
+	hc := &models.Healthcheck{LogPattern: "server ready"}
+	target := Target{LogPath: logPath, LogOffset: int64(len(old))}
+	if err := Check(hc, target); err == nil {
+		t.Error("Expected output from a previous run to be ignored")
+	}
+
+	target.LogOffset = 0
+	if err := Check(hc, target); err != nil {
+		t.Errorf("Expected pattern to match, got: %v", err)
+	}
+}
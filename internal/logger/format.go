@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+// Streams written to service logs. StreamLoex marks lines written by loex
+// itself, such as the start banner.
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+	StreamLoex   = "loex"
+)
+
+const lineTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+type jsonLine struct {
+	TS     string `json:"ts"`
+	Stream string `json:"stream"`
+	Msg    string `json:"msg"`
+}
+
+// FormatLine renders a log line in the given format. Raw lines are
+// returned unchanged.
+func FormatLine(format, stream, text string, t time.Time) []byte {
+	switch format {
+	case models.LogFormatTimestamped:
+		return []byte(fmt.Sprintf("%s [%s] %s\n", t.Format(lineTimeFormat), stream, text))
+	case models.LogFormatJSON:
+		data, err := json.Marshal(jsonLine{TS: t.Format(time.RFC3339Nano), Stream: stream, Msg: text})
+		if err == nil {
+			return append(data, '\n')
+		}
+	}
+	return []byte(text + "\n")
+}
+
+// LineWriter splits output into lines and writes each one to the
+// underlying writer in a log format, tagged with its stream. Every line is
+// written with a single Write, so writers for stdout and stderr can share a
+// log file.
+type LineWriter struct {
+	out    io.Writer
+	format string
+	stream string
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func NewLineWriter(out io.Writer, format, stream string) *LineWriter {
+	return &LineWriter{out: out, format: format, stream: stream}
+}
+
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimSuffix(w.buf[:i], []byte("\r"))
+		if _, err := w.out.Write(FormatLine(w.format, w.stream, string(line), time.Now())); err != nil {
+			return len(p), err
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes any buffered partial line.
+func (w *LineWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := string(w.buf)
+	w.buf = nil
+	_, err := w.out.Write(FormatLine(w.format, w.stream, line, time.Now()))
+	return err
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+func TestLineWriterTimestamped(t *testing.T) {
+	var out bytes.Buffer
+	w := NewLineWriter(&out, models.LogFormatTimestamped, StreamStderr)
+	w.Write([]byte("first\nsec"))
+	w.Write([]byte("ond\npartial"))
+	w.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %q", out.String())
+	}
+	for i, text := range []string{"first", "second", "partial"} {
+		if !strings.HasSuffix(lines[i], " [stderr] "+text) {
+			t.Errorf("Line %d = %q, expected stream marker and %q", i, lines[i], text)
+		}
+		if _, ok := ParseTimestamp(lines[i]); !ok {
+			t.Errorf("Line %d = %q has no readable timestamp", i, lines[i])
+		}
+	}
+}
+
+func TestLineWriterJSON(t *testing.T) {
+	var out bytes.Buffer
+	w := NewLineWriter(&out, models.LogFormatJSON, StreamStdout)
+	w.Write([]byte("hello \"world\"\n"))
+
+	var line jsonLine
+	if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+		t.Fatalf("Invalid JSON line %q: %v", out.String(), err)
+	}
+	if line.Stream != StreamStdout || line.Msg != "hello \"world\"" {
+		t.Errorf("Unexpected line: %+v", line)
+	}
+	if _, ok := ParseTimestamp(strings.TrimSpace(out.String())); !ok {
+		t.Errorf("JSON line %q has no readable timestamp", out.String())
+	}
+}
@@ -22,33 +22,68 @@ func (m *Manager) GetLogsDir(projectName string) string {
 	return m.config.GetLogsPath(projectName)
 }
 
-func (m *Manager) GetLogFile(projectName string, serviceType models.ServiceType) (*os.File, error) {
+// GetLogFile rotates the service log if needed and opens it with a start
+// banner written in the given log format.
+func (m *Manager) GetLogFile(projectName, serviceName, format string) (*os.File, error) {
 	logsDir := m.GetLogsDir(projectName)
 	
 	if err := os.MkdirAll(logsDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create logs directory: %w", err)
 	}
 
-	logPath := filepath.Join(logsDir, fmt.Sprintf("%s.log", serviceType))
+	logPath := filepath.Join(logsDir, fmt.Sprintf("%s.log", serviceName))
+	if err := RotateIfNeeded(logPath, m.Policy(projectName)); err != nil {
+		return nil, err
+	}
 	
 	file, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open log file: %w", err)
 	}
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	fmt.Fprintf(file, "\n=== %s Service Started at %s ===\n", serviceType, timestamp)
+	now := time.Now()
+	banner := fmt.Sprintf("=== %s Service Started at %s ===", serviceName, now.Format("2006-01-02 15:04:05"))
+	if format == "" || format == models.LogFormatRaw {
+		fmt.Fprintf(file, "\n%s\n", banner)
+	} else {
+		file.Write(FormatLine(format, StreamLoex, banner, now))
+	}
 
 	return file, nil
 }
 
-func (m *Manager) GetLogPath(projectName string, serviceType models.ServiceType) string {
-	return filepath.Join(m.GetLogsDir(projectName), fmt.Sprintf("%s.log", serviceType))
+func (m *Manager) GetLogPath(projectName, serviceName string) string {
+	return filepath.Join(m.GetLogsDir(projectName), fmt.Sprintf("%s.log", serviceName))
+}
+
+// Policy returns the log policy of a project, falling back to the global
+// settings and the defaults.
+func (m *Manager) Policy(projectName string) Policy {
+	var global, project *models.LogPolicy
+	if settings, err := m.config.LoadSettings(); err == nil {
+		global = settings.Logs
+	}
+	if p, err := m.config.LoadProject(projectName); err == nil {
+		project = p.Logs
+	}
+	return ResolvePolicy(global, project)
+}
+
+// SupervisorLogName is the log file of the supervisor daemon. The leading
+// underscore keeps it apart from service logs.
+const SupervisorLogName = "_supervisor.log"
+
+func (m *Manager) GetSupervisorLogPath(projectName string) string {
+	return filepath.Join(m.GetLogsDir(projectName), SupervisorLogName)
 }
 
-func (m *Manager) ClearLogs(projectName string, serviceType models.ServiceType) error {
-	logPath := m.GetLogPath(projectName, serviceType)
+func (m *Manager) ClearLogs(projectName, serviceName string) error {
+	logPath := m.GetLogPath(projectName, serviceName)
 	
+	if err := removeRotated(logPath); err != nil {
+		return err
+	}
+
 	if _, err := os.Stat(logPath); os.IsNotExist(err) {
 		return nil
 	}
@@ -73,8 +108,24 @@ func (m *Manager) ClearAllLogs(projectName string) error {
 			if err := os.Truncate(logPath, 0); err != nil {
 				return fmt.Errorf("failed to clear log file %s: %w", file.Name(), err)
 			}
+			if err := removeRotated(logPath); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func removeRotated(logPath string) error {
+	rotated, err := RotatedFiles(logPath)
+	if err != nil {
+		return fmt.Errorf("failed to list rotated logs: %w", err)
+	}
+	for _, path := range rotated {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove rotated log %s: %w", filepath.Base(path), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Line is a single line of a service log with the time it was written.
+// Lines without a timestamp of their own inherit the time of the closest
+// timestamped line before them.
+type Line struct {
+	Service string
+	Time    time.Time
+	Text    string
+}
+
+// ReadOptions selects which lines ReadLines returns. Tail limits the
+// result to the last Tail lines (0 for all). Since and Until, when set,
+// bound the line times. WithTimes resolves the time of every returned line,
+// which is needed to merge logs of several services.
+type ReadOptions struct {
+	Tail      int
+	Since     time.Time
+	Until     time.Time
+	WithTimes bool
+}
+
+// ErrNoTimestamps is returned by ReadLines when Since or Until is set but
+// the log has no timestamped lines, as with raw output of a program that
+// does not print times. Start banners alone only tell when the service
+// started, not when each line was written.
+var ErrNoTimestamps = errors.New("log lines have no timestamps")
+
+const (
+	readChunkSize  = 64 * 1024
+	followInterval = 250 * time.Millisecond
+)
+
+var (
+	bannerPattern    = regexp.MustCompile(`^=== .* Started at (\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}) ===$`)
+	jsonTimePattern  = regexp.MustCompile(`^\{"ts":"([^"]+)"`)
+	timestampPattern = regexp.MustCompile(`^\[?(\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?)`)
+)
+
+// ParseTimestamp extracts the time a log line was written from the start
+// of the line, from the ts field of a JSON line or from a loex start banner.
+func ParseTimestamp(text string) (time.Time, bool) {
+	if m := bannerPattern.FindStringSubmatch(text); m != nil {
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", m[1], time.Local)
+		return t, err == nil
+	}
+	return parseLineTime(text)
+}
+
+// parseLineTime is ParseTimestamp without start banners.
+func parseLineTime(text string) (time.Time, bool) {
+	if m := jsonTimePattern.FindStringSubmatch(text); m != nil {
+		t, err := time.Parse(time.RFC3339Nano, m[1])
+		return t, err == nil
+	}
+
+	m := timestampPattern.FindStringSubmatch(text)
+	if m == nil {
+		return time.Time{}, false
+	}
+	value := strings.Replace(strings.Replace(m[1], " ", "T", 1), ",", ".", 1)
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999Z0700"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04:05.999999999", value, time.Local); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// ReadLines returns lines of a log file, continuing into its rotated files
+// from newest to oldest. The files are read backwards from the end, so only
+// the part needed for Tail and Since is ever read; compressed files are
+// decompressed when reached. With Since or Until it returns ErrNoTimestamps
+// when the lines it read carry no timestamps of their own.
+func ReadLines(path, service string, opts ReadOptions) ([]Line, error) {
+	rotated, err := RotatedFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	for _, file := range rotated {
+		// A file being compressed briefly exists in both forms.
+		if strings.HasSuffix(file, ".gz") && fileExists(strings.TrimSuffix(file, ".gz")) {
+			continue
+		}
+		files = append(files, file)
+	}
+
+	filtered := !opts.Since.IsZero() || !opts.Until.IsZero()
+	needTimes := opts.WithTimes || filtered
+
+	var reversed []Line
+	var pending []Line
+	done := false
+	// timed and untimed tell whether any line read had a timestamp of its
+	// own, or only a time taken from a start banner or none.
+	timed, untimed := false, false
+
+	emit := func(lines []Line) {
+		for _, line := range lines {
+			if done {
+				return
+			}
+			if !opts.Until.IsZero() && !line.Time.IsZero() && line.Time.After(opts.Until) {
+				continue
+			}
+			if !opts.Since.IsZero() && line.Time.Before(opts.Since) {
+				done = true
+				return
+			}
+			reversed = append(reversed, line)
+			if opts.Tail > 0 && len(reversed) >= opts.Tail {
+				done = true
+			}
+		}
+	}
+
+	scan := func(text string) bool {
+		line := Line{Service: service, Text: text}
+		if !needTimes {
+			emit([]Line{line})
+			return !done
+		}
+
+		t, ok := parseLineTime(text)
+		if ok {
+			timed = true
+		} else if t, ok = ParseTimestamp(text); !ok {
+			untimed = true
+			pending = append(pending, line)
+			return true
+		}
+
+		line.Time = t
+		for i := range pending {
+			pending[i].Time = t
+		}
+		emit(append(pending, line))
+		pending = pending[:0]
+		return !done
+	}
+
+	// Lines pending at the top of a file take their time from the end of
+	// the older file before it.
+	for _, file := range files {
+		if done {
+			break
+		}
+		if err := scanFile(file, scan); err != nil {
+			return nil, err
+		}
+	}
+	if filtered && untimed && !timed {
+		return nil, ErrNoTimestamps
+	}
+	emit(pending)
+
+	lines := make([]Line, len(reversed))
+	for i, line := range reversed {
+		lines[len(reversed)-1-i] = line
+	}
+	return lines, nil
+}
+
+// MergeLines orders lines of several services by time, keeping the order
+// within each service, and returns the last tail lines (0 for all).
+func MergeLines(groups [][]Line, tail int) []Line {
+	var merged []Line
+	for _, group := range groups {
+		merged = append(merged, group...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Time.Before(merged[j].Time)
+	})
+	if tail > 0 && len(merged) > tail {
+		merged = merged[len(merged)-tail:]
+	}
+	return merged
+}
+
+// scanFile calls scanBackward for a log file, decompressing gzipped files.
+// A missing file has no lines.
+func scanFile(path string, fn func(text string) bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	if !strings.HasSuffix(path, ".gz") {
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		return scanBackward(file, info.Size(), fn)
+	}
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	defer gz.Close()
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	return scanBackward(bytes.NewReader(data), int64(len(data)), fn)
+}
+
+// scanBackward calls fn for every line of the size bytes of r starting with
+// the last one until fn returns false.
+func scanBackward(r io.ReaderAt, size int64, fn func(text string) bool) error {
+	offset := size
+	var rest []byte
+	first := true
+
+	for offset > 0 {
+		n := int64(readChunkSize)
+		if offset < n {
+			n = offset
+		}
+		offset -= n
+
+		chunk := make([]byte, n, n+int64(len(rest)))
+		if _, err := r.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return err
+		}
+		chunk = append(chunk, rest...)
+
+		for {
+			i := bytes.LastIndexByte(chunk, '\n')
+			if i < 0 {
+				break
+			}
+			text := string(chunk[i+1:])
+			chunk = chunk[:i]
+			if first && text == "" {
+				// The trailing newline of the file does not start a line.
+				first = false
+				continue
+			}
+			first = false
+			if !fn(text) {
+				return nil
+			}
+		}
+		rest = chunk
+	}
+
+	if len(rest) > 0 || !first {
+		fn(string(rest))
+	}
+	return nil
+}
+
+// Follow calls fn for every line appended to the file until ctx is done.
+// It waits for the file to appear, starts over when the file is truncated
+// and switches to the new file when it is rotated.
+func Follow(ctx context.Context, path string, fn func(text string)) error {
+	var file *os.File
+	var reader *bufio.Reader
+	var offset int64
+	var partial []byte
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	open := func(fromEnd bool) {
+		f, err := os.Open(path)
+		if err != nil {
+			return
+		}
+		offset = 0
+		if fromEnd {
+			if offset, err = f.Seek(0, io.SeekEnd); err != nil {
+				f.Close()
+				return
+			}
+		}
+		file = f
+		reader = bufio.NewReader(f)
+		partial = nil
+	}
+
+	drain := func() {
+		for {
+			data, err := reader.ReadBytes('\n')
+			offset += int64(len(data))
+			if err != nil {
+				partial = append(partial, data...)
+				return
+			}
+			line := append(partial, data[:len(data)-1]...)
+			partial = nil
+			fn(string(line))
+		}
+	}
+
+	open(true)
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+
+	for {
+		if file == nil {
+			open(false)
+		}
+
+		if file != nil {
+			drain()
+
+			current, err := file.Stat()
+			latest, statErr := os.Stat(path)
+			switch {
+			case statErr == nil && err == nil && !os.SameFile(current, latest):
+				// Rotated: the old file is complete, continue with the new one.
+				drain()
+				file.Close()
+				file = nil
+				open(false)
+			case err == nil && current.Size() < offset:
+				// Truncated: start reading from the beginning again.
+				file.Seek(0, io.SeekStart)
+				reader.Reset(file)
+				offset = 0
+				partial = nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
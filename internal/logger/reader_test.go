@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeLog(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "service.log")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func texts(lines []Line) []string {
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		result[i] = line.Text
+	}
+	return result
+}
+
+func TestReadLinesTail(t *testing.T) {
+	var lines []string
+	for i := 0; i < 20000; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	path := writeLog(t, lines...)
+
+	got, err := ReadLines(path, "api", ReadOptions{Tail: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"line 19997", "line 19998", "line 19999"}
+	if strings.Join(texts(got), ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, texts(got))
+	}
+
+	all, err := ReadLines(path, "api", ReadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 20000 || all[0].Text != "line 0" {
+		t.Errorf("Expected all 20000 lines starting with 'line 0', got %d", len(all))
+	}
+}
+
+func TestReadLinesSinceUntil(t *testing.T) {
+	path := writeLog(t,
+		"=== api Service Started at 2024-01-02 10:00:00 ===",
+		"2024-01-02 10:00:01 booting",
+		"continued",
+		"2024-01-02 10:00:05 ready",
+		"2024-01-02 10:00:09 request",
+	)
+
+	since := time.Date(2024, 1, 2, 10, 0, 1, 0, time.Local)
+	until := time.Date(2024, 1, 2, 10, 0, 5, 0, time.Local)
+	got, err := ReadLines(path, "api", ReadOptions{Since: since, Until: until})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"2024-01-02 10:00:01 booting", "continued", "2024-01-02 10:00:05 ready"}
+	if strings.Join(texts(got), ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, texts(got))
+	}
+	if !got[1].Time.Equal(since) {
+		t.Errorf("Expected untimed line to inherit %v, got %v", since, got[1].Time)
+	}
+}
+
+func TestReadLinesSinceRaw(t *testing.T) {
+	path := writeLog(t,
+		"=== api Service Started at 2024-01-02 10:00:00 ===",
+		"listening on :8080",
+		"GET /",
+	)
+
+	since := time.Date(2024, 1, 2, 10, 30, 0, 0, time.Local)
+	if _, err := ReadLines(path, "api", ReadOptions{Since: since}); !errors.Is(err, ErrNoTimestamps) {
+		t.Errorf("Expected ErrNoTimestamps for raw lines with Since, got %v", err)
+	}
+
+	// Without a time filter raw lines are read as usual.
+	got, err := ReadLines(path, "api", ReadOptions{WithTimes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || !got[2].Time.Equal(time.Date(2024, 1, 2, 10, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected raw lines to take the start time, got %+v", got)
+	}
+}
+
+func TestReadLinesRotated(t *testing.T) {
+	path := writeLog(t,
+		"2024-01-02 10:00:07 line 7",
+		"2024-01-02 10:00:08 line 8",
+	)
+	older := path + ".20240102-100006"
+	if err := os.WriteFile(older, []byte("2024-01-02 10:00:04 line 4\n2024-01-02 10:00:05 line 5\n2024-01-02 10:00:06 line 6\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	oldest := path + ".20240102-100003"
+	if err := os.WriteFile(oldest, []byte("2024-01-02 10:00:01 line 1\n2024-01-02 10:00:02 line 2\n2024-01-02 10:00:03 line 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := compressFile(oldest); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadLines(path, "api", ReadOptions{Tail: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "5,6,7,8"; lineNumbers(got) != expected {
+		t.Errorf("Expected lines %s, got %s", expected, lineNumbers(got))
+	}
+
+	since := time.Date(2024, 1, 2, 10, 0, 2, 0, time.Local)
+	got, err = ReadLines(path, "api", ReadOptions{Since: since})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "2,3,4,5,6,7,8"; lineNumbers(got) != expected {
+		t.Errorf("Expected lines %s, got %s", expected, lineNumbers(got))
+	}
+}
+
+func lineNumbers(lines []Line) string {
+	numbers := make([]string, len(lines))
+	for i, line := range lines {
+		numbers[i] = line.Text[strings.LastIndexByte(line.Text, ' ')+1:]
+	}
+	return strings.Join(numbers, ",")
+}
+
+func TestMergeLines(t *testing.T) {
+	base := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	api := []Line{
+		{Service: "api", Time: base, Text: "a1"},
+		{Service: "api", Time: base.Add(2 * time.Second), Text: "a2"},
+	}
+	db := []Line{
+		{Service: "db", Time: base.Add(time.Second), Text: "d1"},
+		{Service: "db", Time: base.Add(3 * time.Second), Text: "d2"},
+	}
+
+	got := texts(MergeLines([][]Line{api, db}, 3))
+	expected := []string{"d1", "a2", "d2"}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
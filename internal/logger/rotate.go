@@ -0,0 +1,324 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+const (
+	DefaultMaxSizeMB = 50
+	DefaultMaxFiles  = 5
+
+	rotatedTimeFormat = "20060102-150405"
+)
+
+var rotatedSuffixPattern = regexp.MustCompile(`^\.\d{8}-\d{6}(-\d+)?(\.gz)?$`)
+
+// Policy is the effective rotation and retention policy of a log file.
+// A zero MaxAge disables age based rotation and removal.
+type Policy struct {
+	MaxSize  int64
+	MaxFiles int
+	MaxAge   time.Duration
+	Compress bool
+}
+
+// ResolvePolicy merges the project policy over the global one and fills in
+// the defaults.
+func ResolvePolicy(global, project *models.LogPolicy) Policy {
+	policy := Policy{MaxSize: DefaultMaxSizeMB << 20, MaxFiles: DefaultMaxFiles}
+	for _, p := range []*models.LogPolicy{global, project} {
+		if p == nil {
+			continue
+		}
+		if p.MaxSizeMB > 0 {
+			policy.MaxSize = int64(p.MaxSizeMB) << 20
+		}
+		if p.MaxFiles > 0 {
+			policy.MaxFiles = p.MaxFiles
+		}
+		if p.MaxAge > 0 {
+			policy.MaxAge = time.Duration(p.MaxAge)
+		}
+		if p.Compress != nil {
+			policy.Compress = *p.Compress
+		}
+	}
+	return policy
+}
+
+func (p Policy) String() string {
+	s := fmt.Sprintf("max size %dMB, keep %d files", p.MaxSize>>20, p.MaxFiles)
+	if p.MaxAge > 0 {
+		s += fmt.Sprintf(", max age %s", p.MaxAge)
+	}
+	if p.Compress {
+		s += ", gzip"
+	}
+	return s
+}
+
+// RotatingWriter appends to a log file and rotates it according to its
+// policy while it is being written. Rotated files are renamed to
+// <name>.<timestamp> and optionally compressed in the background.
+type RotatingWriter struct {
+	path   string
+	policy Policy
+
+	mu      sync.Mutex
+	file    *os.File
+	size    int64
+	started time.Time
+	// background tracks compression and cleanup after a rotation.
+	background sync.WaitGroup
+}
+
+func NewRotatingWriter(path string, policy Policy) (*RotatingWriter, error) {
+	w := &RotatingWriter{path: path, policy: policy}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the log file and waits for pending compression.
+func (w *RotatingWriter) Close() error {
+	w.mu.Lock()
+	err := w.file.Close()
+	w.mu.Unlock()
+
+	w.background.Wait()
+	return err
+}
+
+func (w *RotatingWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	w.file = file
+	w.size = 0
+	w.started = time.Now()
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		w.size = info.Size()
+		w.started = firstLineTime(w.path, info.ModTime())
+	}
+	return nil
+}
+
+func (w *RotatingWriter) shouldRotate(incoming int64) bool {
+	if w.policy.MaxAge > 0 && w.size > 0 && time.Since(w.started) > w.policy.MaxAge {
+		return true
+	}
+	if w.size == 0 || w.size+incoming <= w.policy.MaxSize {
+		return false
+	}
+
+	// The file may have been cleared by someone else since it was opened.
+	if info, err := w.file.Stat(); err == nil {
+		w.size = info.Size()
+	}
+	return w.size > 0 && w.size+incoming > w.policy.MaxSize
+}
+
+func (w *RotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+
+	rotated, err := renameRotated(w.path)
+	if err != nil {
+		return err
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	w.background.Add(1)
+	go func() {
+		defer w.background.Done()
+		finishRotation(w.path, rotated, w.policy)
+	}()
+	return nil
+}
+
+// RotateIfNeeded rotates the log file at path when it exceeds the policy.
+// It is called before a service is started so that logs of stopped
+// services are rotated as well.
+func RotateIfNeeded(path string, policy Policy) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	tooBig := info.Size() >= policy.MaxSize
+	tooOld := policy.MaxAge > 0 && info.Size() > 0 && time.Since(firstLineTime(path, info.ModTime())) > policy.MaxAge
+	if !tooBig && !tooOld {
+		removeExpired(path, policy)
+		return nil
+	}
+
+	rotated, err := renameRotated(path)
+	if err != nil {
+		return err
+	}
+	finishRotation(path, rotated, policy)
+	return nil
+}
+
+// RotatedFiles returns the rotated files of the log at path, newest first.
+func RotatedFiles(path string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	base := filepath.Base(path)
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, base) {
+			continue
+		}
+		if rotatedSuffixPattern.MatchString(name[len(base):]) {
+			files = append(files, filepath.Join(filepath.Dir(path), name))
+		}
+	}
+
+	// Compression does not change the order, so the .gz suffix is ignored.
+	sort.SliceStable(files, func(i, j int) bool {
+		return strings.TrimSuffix(files[i], ".gz") > strings.TrimSuffix(files[j], ".gz")
+	})
+	return files, nil
+}
+
+func renameRotated(path string) (string, error) {
+	rotated := path + "." + time.Now().Format(rotatedTimeFormat)
+	for i := 1; fileExists(rotated) || fileExists(rotated+".gz"); i++ {
+		rotated = fmt.Sprintf("%s.%s-%d", path, time.Now().Format(rotatedTimeFormat), i)
+	}
+
+	if err := os.Rename(path, rotated); err != nil {
+		return "", fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	return rotated, nil
+}
+
+func finishRotation(path, rotated string, policy Policy) {
+	if policy.Compress {
+		compressFile(rotated)
+	}
+	removeExpired(path, policy)
+}
+
+// removeExpired removes rotated files beyond MaxFiles or older than MaxAge.
+func removeExpired(path string, policy Policy) {
+	files, err := RotatedFiles(path)
+	if err != nil {
+		return
+	}
+
+	for i, file := range files {
+		expired := i >= policy.MaxFiles
+		if !expired && policy.MaxAge > 0 {
+			if info, err := os.Stat(file); err == nil && time.Since(info.ModTime()) > policy.MaxAge {
+				expired = true
+			}
+		}
+		if expired {
+			os.Remove(file)
+		}
+	}
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmpPath := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if info, err := src.Stat(); err == nil {
+		os.Chtimes(tmpPath, info.ModTime(), info.ModTime())
+	}
+	if err := os.Rename(tmpPath, path+".gz"); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Remove(path)
+}
+
+// firstLineTime returns the time of the first timestamped line near the
+// start of the file, or fallback if there is none.
+func firstLineTime(path string, fallback time.Time) time.Time {
+	file, err := os.Open(path)
+	if err != nil {
+		return fallback
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(io.LimitReader(file, 4096))
+	for scanner.Scan() {
+		if t, ok := ParseTimestamp(scanner.Text()); ok {
+			return t
+		}
+	}
+	return fallback
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
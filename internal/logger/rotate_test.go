@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+func TestResolvePolicy(t *testing.T) {
+	compress := true
+	noCompress := false
+	global := &models.LogPolicy{MaxSizeMB: 10, MaxAge: models.Duration(time.Hour), Compress: &compress}
+	project := &models.LogPolicy{MaxFiles: 2, Compress: &noCompress}
+
+	policy := ResolvePolicy(global, project)
+	expected := Policy{MaxSize: 10 << 20, MaxFiles: 2, MaxAge: time.Hour, Compress: false}
+	if policy != expected {
+		t.Errorf("Expected %+v, got %+v", expected, policy)
+	}
+
+	if defaults := ResolvePolicy(nil, nil); defaults.MaxSize != DefaultMaxSizeMB<<20 || defaults.MaxFiles != DefaultMaxFiles {
+		t.Errorf("Unexpected defaults: %+v", defaults)
+	}
+}
+
+func TestRotatingWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.log")
+	w, err := NewRotatingWriter(path, Policy{MaxSize: 100, MaxFiles: 2, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	line := strings.Repeat("x", 59) + "\n"
+	for i := 0; i < 6; i++ {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+		// Rotated files are named by the second they were rotated in.
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != line {
+		t.Errorf("Expected the active log to hold one line, got %d bytes", len(data))
+	}
+
+	rotated, err := RotatedFiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotated) != 2 {
+		t.Fatalf("Expected 2 rotated files to be kept, got %v", rotated)
+	}
+	for _, file := range rotated {
+		if !strings.HasSuffix(file, ".gz") {
+			t.Errorf("Expected rotated file %s to be compressed", file)
+		}
+	}
+}
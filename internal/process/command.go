@@ -0,0 +1,167 @@
+package process
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+// DefaultShell runs services in shell mode and commands that need a shell.
+const DefaultShell = "/bin/sh"
+
+// CommandArgv returns the argv used to run a service. The explicit Args
+// form is used as is; in shell mode the command is passed to the shell.
+// Otherwise the command string is split with shell quoting rules, falling
+// back to the default shell when it uses pipes, redirection, variables or
+// other shell syntax.
+func CommandArgv(service models.Service) ([]string, error) {
+	if len(service.Args) > 0 {
+		return service.Args, nil
+	}
+
+	if strings.TrimSpace(service.Command) == "" {
+		return nil, fmt.Errorf("empty command")
+	}
+	if service.Shell != "" {
+		return []string{service.Shell, "-c", service.Command}, nil
+	}
+
+	argv, needsShell, err := SplitCommand(service.Command)
+	if err != nil {
+		return nil, err
+	}
+	if needsShell {
+		return []string{DefaultShell, "-c", service.Command}, nil
+	}
+	return argv, nil
+}
+
+// SplitCommand splits a command string into words the way a POSIX shell
+// does for plain commands: words are separated by unquoted whitespace,
+// single quotes preserve everything, double quotes and backslashes escape.
+// needsShell reports syntax that only a shell can run, such as pipes,
+// &&, redirection, variable expansion, globs or leading VAR=value
+// assignments.
+func SplitCommand(command string) (argv []string, needsShell bool, err error) {
+	var word strings.Builder
+	inWord := false
+	// assignments is true while only VAR=value words have been seen.
+	assignments := true
+
+	finishWord := func() {
+		if !inWord {
+			return
+		}
+		w := word.String()
+		if assignments && isAssignment(w) {
+			needsShell = true
+		} else {
+			assignments = false
+		}
+		argv = append(argv, w)
+		word.Reset()
+		inWord = false
+	}
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			finishWord()
+
+		case c == '\'':
+			inWord = true
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, false, fmt.Errorf("unterminated single quote in command: %s", command)
+			}
+			word.WriteString(string(runes[i+1 : end]))
+			i = end
+
+		case c == '"':
+			inWord = true
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				switch {
+				case runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]):
+					i++
+					if runes[i] != '\n' {
+						word.WriteRune(runes[i])
+					}
+				case runes[i] == '$' || runes[i] == '`':
+					needsShell = true
+					word.WriteRune(runes[i])
+				default:
+					word.WriteRune(runes[i])
+				}
+			}
+			if i >= len(runes) {
+				return nil, false, fmt.Errorf("unterminated double quote in command: %s", command)
+			}
+
+		case c == '\\':
+			inWord = true
+			if i+1 < len(runes) {
+				i++
+				if runes[i] != '\n' {
+					word.WriteRune(runes[i])
+				}
+			}
+
+		case strings.ContainsRune("|&;<>()$`*?[", c):
+			needsShell = true
+			inWord = true
+			word.WriteRune(c)
+
+		case (c == '~' || c == '#') && !inWord:
+			needsShell = true
+			inWord = true
+			word.WriteRune(c)
+
+		default:
+			inWord = true
+			word.WriteRune(c)
+		}
+	}
+	finishWord()
+
+	return argv, needsShell, nil
+}
+
+// QuoteCommand joins argv into a command string that SplitCommand splits
+// back into the same words.
+func QuoteCommand(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\|&;<>()$`*?[#~") {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+func isAssignment(word string) bool {
+	i := strings.IndexByte(word, '=')
+	if i <= 0 {
+		return false
+	}
+	for j, c := range word[:i] {
+		if c != '_' && (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') && (j == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
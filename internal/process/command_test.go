@@ -0,0 +1,76 @@
+package process
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		command    string
+		argv       []string
+		needsShell bool
+	}{
+		{"npm run dev", []string{"npm", "run", "dev"}, false},
+		{`node server.js --name "my api"`, []string{"node", "server.js", "--name", "my api"}, false},
+		{`echo 'it''s' "a \"b\"" c\ d`, []string{"echo", "its", `a "b"`, "c d"}, false},
+		{"go run . --port=8080", []string{"go", "run", ".", "--port=8080"}, false},
+		{"docker build -t local-db . && docker run -d local-db", nil, true},
+		{"npm start | tee out.log", nil, true},
+		{"PORT=3000 npm start", nil, true},
+		{`echo "$HOME"`, nil, true},
+		{"echo '$HOME'", []string{"echo", "$HOME"}, false},
+		{"ls *.go", nil, true},
+	}
+
+	for _, tt := range tests {
+		argv, needsShell, err := SplitCommand(tt.command)
+		if err != nil {
+			t.Errorf("SplitCommand(%q) failed: %v", tt.command, err)
+			continue
+		}
+		if needsShell != tt.needsShell {
+			t.Errorf("SplitCommand(%q) needsShell = %v, expected %v", tt.command, needsShell, tt.needsShell)
+		}
+		if tt.argv != nil && !reflect.DeepEqual(argv, tt.argv) {
+			t.Errorf("SplitCommand(%q) = %q, expected %q", tt.command, argv, tt.argv)
+		}
+	}
+
+	if _, _, err := SplitCommand(`echo "unterminated`); err == nil {
+		t.Error("Expected error for unterminated quote")
+	}
+}
+
+func TestQuoteCommand(t *testing.T) {
+	argv := []string{"node", "server.js", "--name", "my api", "it's", ""}
+	split, needsShell, err := SplitCommand(QuoteCommand(argv))
+	if err != nil || needsShell || !reflect.DeepEqual(split, argv) {
+		t.Errorf("QuoteCommand(%q) did not round trip: %q (needsShell %v, err %v)", argv, split, needsShell, err)
+	}
+}
+
+func TestCommandArgv(t *testing.T) {
+	tests := []struct {
+		service models.Service
+		argv    []string
+	}{
+		{models.Service{Command: "ignored", Args: []string{"node", "a b.js"}}, []string{"node", "a b.js"}},
+		{models.Service{Command: "npm start", Shell: "/bin/bash"}, []string{"/bin/bash", "-c", "npm start"}},
+		{models.Service{Command: "make build && ./app"}, []string{DefaultShell, "-c", "make build && ./app"}},
+		{models.Service{Command: "python 'my app.py'"}, []string{"python", "my app.py"}},
+	}
+
+	for _, tt := range tests {
+		argv, err := CommandArgv(tt.service)
+		if err != nil {
+			t.Errorf("CommandArgv(%+v) failed: %v", tt.service, err)
+			continue
+		}
+		if !reflect.DeepEqual(argv, tt.argv) {
+			t.Errorf("CommandArgv(%+v) = %q, expected %q", tt.service, argv, tt.argv)
+		}
+	}
+}
@@ -1,16 +1,20 @@
 package process
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/kjunh972/loex/internal/config"
+	"github.com/kjunh972/loex/internal/env"
+	"github.com/kjunh972/loex/internal/health"
 	"github.com/kjunh972/loex/internal/logger"
 	"github.com/kjunh972/loex/pkg/models"
 )
@@ -18,6 +22,15 @@ import (
 type Manager struct {
 	config *config.Manager
 	logger *logger.Manager
+
+	launchHook   func(serviceName string, cmd *exec.Cmd)
+	outputFor    func(serviceName string) io.Writer
+	portConflict func(conflict *PortConflictError) bool
+
+	// canceled stops StartServices from launching further services.
+	canceled atomic.Bool
+
+	forceStop bool
 }
 
 func NewManager(config *config.Manager, logger *logger.Manager) *Manager {
@@ -27,103 +40,373 @@ func NewManager(config *config.Manager, logger *logger.Manager) *Manager {
 	}
 }
 
-func (m *Manager) StartService(projectName string, serviceType models.ServiceType) error {
+// SetLaunchHook registers a function that is called with every child
+// process started by this manager. The supervisor daemon uses it to take
+// ownership of the processes it starts and wait for them to exit.
+func (m *Manager) SetLaunchHook(hook func(serviceName string, cmd *exec.Cmd)) {
+	m.launchHook = hook
+}
+
+// SetForceStop makes StopService kill services with SIGKILL right away
+// instead of sending their stop signal.
+func (m *Manager) SetForceStop(force bool) {
+	m.forceStop = force
+}
+
+// SetOutput registers a function returning an extra writer that receives a
+// copy of each service's output in addition to its log file. Output is then
+// copied by this process, so the caller has to stay alive and wait for the
+// children, e.g. through SetLaunchHook.
+func (m *Manager) SetOutput(outputFor func(serviceName string) io.Writer) {
+	m.outputFor = outputFor
+}
+
+// CancelStarts makes StartServices stop launching services and stop
+// waiting for services to become ready.
+func (m *Manager) CancelStarts() {
+	m.canceled.Store(true)
+}
+
+func (m *Manager) StartService(projectName, serviceName string) error {
 	project, err := m.config.LoadProject(projectName)
 	if err != nil {
 		return fmt.Errorf("failed to load project: %w", err)
 	}
 
-	service, exists := project.Services[serviceType]
+	service, exists := project.Services[serviceName]
 	if !exists {
-		return fmt.Errorf("service %s not configured for project %s", serviceType, projectName)
+		return fmt.Errorf("service %s not configured for project %s", serviceName, projectName)
 	}
 
-	if isRunning, _ := m.IsServiceRunning(projectName, serviceType); isRunning {
-		return fmt.Errorf("service %s is already running for project %s", serviceType, projectName)
+	if isRunning, _ := m.IsServiceRunning(projectName, serviceName); isRunning {
+		return fmt.Errorf("service %s is already running for project %s", serviceName, projectName)
 	}
 
-	parts := strings.Fields(service.Command)
-	if len(parts) == 0 {
-		return fmt.Errorf("empty command for service %s", serviceType)
+	ports := service.Ports
+	for _, port := range NamedPorts(service, nil) {
+		ports = append(ports, port)
+	}
+	if err := m.checkPorts(serviceName, ports); err != nil {
+		return err
+	}
+
+	allocated, err := m.allocatePorts(project, serviceName)
+	if err != nil {
+		return err
 	}
 
-	cmd := exec.Command(parts[0], parts[1:]...)
+	argv, err := CommandArgv(service)
+	if err != nil {
+		return fmt.Errorf("invalid command for service %s: %w", serviceName, err)
+	}
+
+	cmd, err := shimCommand(projectName, serviceName, service.LogFormat, argv)
+	if err != nil {
+		return err
+	}
 	cmd.Dir = service.Dir
 
-	logFile, err := m.logger.GetLogFile(projectName, serviceType)
+	environment, err := env.ForService(project, service, PortEnv(project, serviceName, allocated))
+	if err != nil {
+		return fmt.Errorf("failed to resolve environment for service %s: %w", serviceName, err)
+	}
+	cmd.Env = environment.Environ()
+
+	logFile, err := m.logger.GetLogFile(projectName, serviceName, service.LogFormat)
 	if err != nil {
 		return fmt.Errorf("failed to create log file: %w", err)
 	}
 
-	cmd.Stdout = logFile
-	cmd.Stderr = logFile
+	var logOffset int64
+	if info, err := logFile.Stat(); err == nil {
+		logOffset = info.Size()
+	}
+	logFile.Close()
+
+	// The shim appends the output to the log file; the extra output only
+	// gets a copy.
+	if m.outputFor != nil {
+		cmd.Stdout = m.outputFor(serviceName)
+	}
 
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setpgid: true,
 	}
 
 	if err := cmd.Start(); err != nil {
-		logFile.Close()
-		return fmt.Errorf("failed to start service %s: %w", serviceType, err)
+		return fmt.Errorf("failed to start service %s: %w", serviceName, err)
 	}
 
-	if err := m.savePID(projectName, serviceType, cmd.Process.Pid, service.Command); err != nil {
+	if err := m.savePID(projectName, serviceName, cmd.Process.Pid, service.Command, logOffset); err != nil {
 		cmd.Process.Kill()
 		return fmt.Errorf("failed to save PID: %w", err)
 	}
 
-	fmt.Printf("Started %s service for project '%s' (PID: %d)\n", serviceType, projectName, cmd.Process.Pid)
+	if m.launchHook != nil {
+		m.launchHook(serviceName, cmd)
+	} else {
+		// Reap the child so an early exit is noticed below.
+		go cmd.Wait()
+	}
+
+	fmt.Printf("Started %s service for project '%s' (PID: %d)\n", serviceName, projectName, cmd.Process.Pid)
 	
 	time.Sleep(500 * time.Millisecond)
 	if !m.isProcessRunning(cmd.Process.Pid) {
-		logPath := filepath.Join(m.logger.GetLogsDir(projectName), fmt.Sprintf("%s.log", serviceType))
-		fmt.Printf("Service '%s' failed to start (exited immediately)\n", serviceType)
+		logPath := filepath.Join(m.logger.GetLogsDir(projectName), fmt.Sprintf("%s.log", serviceName))
+		fmt.Printf("Service '%s' failed to start (exited immediately)\n", serviceName)
 		fmt.Printf("Check logs: %s\n", logPath)
+		return fmt.Errorf("service %s exited immediately", serviceName)
 	}
 	
 	return nil
 }
 
-func (m *Manager) StopService(projectName string, serviceType models.ServiceType) error {
+// WaitReady blocks until the health check of a started service passes.
+// Services without a health check are ready as soon as they are running.
+func (m *Manager) WaitReady(projectName, serviceName string) error {
+	project, err := m.config.LoadProject(projectName)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+
+	service, exists := project.Services[serviceName]
+	if !exists || service.Healthcheck == nil {
+		return nil
+	}
+
+	processInfo, err := m.GetProcessDetails(projectName, serviceName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Waiting for %s to become ready (%s)...\n", serviceName, health.Describe(service.Healthcheck))
+	target := m.healthTarget(project, serviceName, processInfo)
+	alive := func() bool { return !m.canceled.Load() && IsServiceProcess(*processInfo) }
+	if err := health.Wait(service.Healthcheck, target, alive); err != nil {
+		return fmt.Errorf("service %s is unhealthy: %w", serviceName, err)
+	}
+
+	fmt.Printf("Service '%s' is ready\n", serviceName)
+	return nil
+}
+
+// StartServices starts the given services in dependency order and returns
+// the error for every service that was not started. Each service waits for
+// the services it depends on to become ready, so independent services are
+// started in parallel. A service is skipped when one of its declared
+// dependencies failed. Services listed in skipRunning are not treated as
+// failed when they are already running.
+func (m *Manager) StartServices(project *models.Project, names []string, skipRunning map[string]bool) (map[string]error, error) {
+	levels, err := project.StartLevels(names)
+	if err != nil {
+		return nil, err
+	}
+	waitFor := startDependencies(project, levels)
+
+	done := make(map[string]chan struct{}, len(names))
+	for _, serviceName := range names {
+		done[serviceName] = make(chan struct{})
+	}
+
+	failed := make(map[string]error)
+	var mu sync.Mutex
+	setFailed := func(serviceName string, err error) {
+		mu.Lock()
+		failed[serviceName] = err
+		mu.Unlock()
+	}
+	hasFailed := func(serviceName string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		_, ok := failed[serviceName]
+		return ok
+	}
+
+	var wg sync.WaitGroup
+	for _, serviceName := range names {
+		wg.Add(1)
+		go func(serviceName string) {
+			defer wg.Done()
+			defer close(done[serviceName])
+
+			declared := project.Services[serviceName].DependsOn
+			for _, dep := range waitFor[serviceName] {
+				<-done[dep]
+				if hasFailed(dep) && containsName(declared, dep) {
+					setFailed(serviceName, fmt.Errorf("dependency %s failed to start", dep))
+					return
+				}
+			}
+
+			if m.canceled.Load() {
+				setFailed(serviceName, fmt.Errorf("start canceled"))
+				return
+			}
+
+			if skipRunning[serviceName] {
+				if isRunning, _ := m.IsServiceRunning(project.Name, serviceName); isRunning {
+					fmt.Printf("Service '%s' is already running\n", serviceName)
+					return
+				}
+			}
+
+			if err := m.StartService(project.Name, serviceName); err != nil {
+				setFailed(serviceName, err)
+				return
+			}
+			if err := m.WaitReady(project.Name, serviceName); err != nil {
+				setFailed(serviceName, err)
+			}
+		}(serviceName)
+	}
+	wg.Wait()
+
+	return failed, nil
+}
+
+// startDependencies returns the services each service has to wait for.
+// Projects that declare depends_on wait for their declared dependencies;
+// legacy projects wait for the whole previous level.
+func startDependencies(project *models.Project, levels [][]string) map[string][]string {
+	waitFor := make(map[string][]string)
+	selected := make(map[string]bool)
+	for _, level := range levels {
+		for _, serviceName := range level {
+			selected[serviceName] = true
+		}
+	}
+
+	for i, level := range levels {
+		for _, serviceName := range level {
+			if project.HasDependencies() {
+				for _, dep := range project.Services[serviceName].DependsOn {
+					if selected[dep] {
+						waitFor[serviceName] = append(waitFor[serviceName], dep)
+					}
+				}
+			} else if i > 0 {
+				waitFor[serviceName] = levels[i-1]
+			}
+		}
+	}
+	return waitFor
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// healthTarget describes a started service to its health check, including
+// the environment it was started with, so probes can use its ports.
+func (m *Manager) healthTarget(project *models.Project, serviceName string, processInfo *models.ProcessInfo) health.Target {
+	service := project.Services[serviceName]
+	target := health.Target{
+		Dir:       service.Dir,
+		LogPath:   m.logger.GetLogPath(project.Name, serviceName),
+		LogOffset: processInfo.LogOffset,
+	}
+	if environment, err := env.ForService(project, service, PortEnv(project, serviceName, m.AllocatedPorts(project.Name))); err == nil {
+		target.Env = environment
+	}
+	return target
+}
+
+func (m *Manager) StopService(projectName, serviceName string) error {
 	pids, err := m.config.LoadProjectPIDs(projectName)
 	if err != nil {
 		return fmt.Errorf("failed to load PIDs: %w", err)
 	}
 
-	processInfo, exists := pids.Services[serviceType]
+	processInfo, exists := pids.Services[serviceName]
 	if !exists {
-		return fmt.Errorf("no running process found for service %s", serviceType)
+		return fmt.Errorf("no running process found for service %s", serviceName)
+	}
+
+	// Removing a restarting entry cancels the pending restart.
+	if processInfo.Status == StatusExited || processInfo.Status == StatusRestarting {
+		return m.removePID(projectName, serviceName, processInfo.PID)
 	}
 
-	if !isProcessRunning(processInfo.PID) {
-		delete(pids.Services, serviceType)
-		m.config.SaveProjectPIDs(pids)
+	if !IsProcessRunning(processInfo.PID) {
+		m.removePID(projectName, serviceName, processInfo.PID)
 		return fmt.Errorf("process %d is not running", processInfo.PID)
 	}
+	if !IsServiceProcess(processInfo) {
+		m.removePID(projectName, serviceName, processInfo.PID)
+		return fmt.Errorf("process %d is no longer the %s service (PID reused), not signaling it", processInfo.PID, serviceName)
+	}
+	// Without a recorded identity the PID may belong to any program.
+	if !HasIdentity(processInfo) {
+		return fmt.Errorf("process %d cannot be verified as the %s service, not signaling it; stop it manually if it is", processInfo.PID, serviceName)
+	}
 
-	pgid, err := syscall.Getpgid(processInfo.PID)
-	if err != nil {
-		process, err := os.FindProcess(processInfo.PID)
-		if err != nil {
-			return fmt.Errorf("failed to find process %d: %w", processInfo.PID, err)
-		}
-		if err := process.Signal(syscall.SIGTERM); err != nil {
-			process.Kill()
+	// Mark the stop as intentional so the supervisor does not restart it.
+	m.UpdateProcessInfo(projectName, serviceName, func(info *models.ProcessInfo) {
+		info.Status = StatusStopping
+	})
+
+	stopSignal := syscall.SIGTERM
+	timeout := DefaultStopTimeout
+	if project, err := m.config.LoadProject(projectName); err == nil {
+		if service, exists := project.Services[serviceName]; exists {
+			if sig, err := ParseSignal(service.StopSignal); err == nil {
+				stopSignal = sig
+			}
+			if service.StopTimeout > 0 {
+				timeout = time.Duration(service.StopTimeout)
+			}
 		}
-	} else {
-		if err := syscall.Kill(-pgid, syscall.SIGTERM); err != nil {
-			syscall.Kill(-pgid, syscall.SIGKILL)
+	}
+	if m.forceStop {
+		stopSignal = syscall.SIGKILL
+	}
+
+	// Services run in their own process group, so the signal reaches the
+	// command and everything it started.
+	signal := func(sig syscall.Signal) error { return syscall.Kill(-processInfo.PID, sig) }
+	gone := func(timeout time.Duration) bool { return waitGroupExit(processInfo.PID, timeout) }
+	if pgid, err := syscall.Getpgid(processInfo.PID); err != nil || pgid != processInfo.PID {
+		signal = func(sig syscall.Signal) error { return syscall.Kill(processInfo.PID, sig) }
+		gone = func(timeout time.Duration) bool {
+			deadline := time.Now().Add(timeout)
+			for IsProcessRunning(processInfo.PID) {
+				if time.Now().After(deadline) {
+					return false
+				}
+				time.Sleep(100 * time.Millisecond)
+			}
+			return true
 		}
 	}
 
-	time.Sleep(1 * time.Second)
+	result := "exited"
+	if err := signal(stopSignal); err != nil && err != syscall.ESRCH {
+		return fmt.Errorf("failed to send %v to process %d: %w", stopSignal, processInfo.PID, err)
+	}
+	if !gone(timeout) {
+		if stopSignal != syscall.SIGKILL {
+			fmt.Printf("Service '%s' did not exit within %s, sending SIGKILL\n", serviceName, timeout)
+			signal(syscall.SIGKILL)
+			result = "killed"
+		}
+		if !gone(killTimeout) {
+			return fmt.Errorf("process %d is still running", processInfo.PID)
+		}
+	} else if stopSignal == syscall.SIGKILL {
+		result = "killed"
+	}
 
-	delete(pids.Services, serviceType)
-	if err := m.config.SaveProjectPIDs(pids); err != nil {
+	if err := m.removePID(projectName, serviceName, processInfo.PID); err != nil {
 		return fmt.Errorf("failed to update PID file: %w", err)
 	}
 
-	fmt.Printf("Stopped %s service for project '%s'\n", serviceType, projectName)
+	fmt.Printf("Stopped %s service for project '%s' (%s)\n", serviceName, projectName, result)
 	return nil
 }
 
@@ -138,9 +421,9 @@ func (m *Manager) StopAllServices(projectName string) error {
 	}
 
 	var errors []string
-	for serviceType := range pids.Services {
-		if err := m.StopService(projectName, serviceType); err != nil {
-			errors = append(errors, fmt.Sprintf("%s: %v", serviceType, err))
+	for _, serviceName := range m.stopOrder(projectName, pids) {
+		if err := m.StopService(projectName, serviceName); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", serviceName, err))
 		}
 	}
 
@@ -151,10 +434,101 @@ func (m *Manager) StopAllServices(projectName string) error {
 	return nil
 }
 
-func (m *Manager) GetServiceStatus(projectName string, serviceType models.ServiceType) (string, error) {
+// stopOrder returns the recorded services ordered so that dependents are
+// stopped before their dependencies. Services no longer present in the
+// project configuration are stopped last.
+func (m *Manager) stopOrder(projectName string, pids *models.ProjectPIDs) []string {
+	var order []string
+	seen := make(map[string]bool)
+
+	if project, err := m.config.LoadProject(projectName); err == nil {
+		var names []string
+		for serviceName := range pids.Services {
+			if _, exists := project.Services[serviceName]; exists {
+				names = append(names, serviceName)
+			}
+		}
+		if levels, err := project.StopLevels(names); err == nil {
+			for _, level := range levels {
+				for _, serviceName := range level {
+					order = append(order, serviceName)
+					seen[serviceName] = true
+				}
+			}
+		}
+	}
+
+	for serviceName := range pids.Services {
+		if !seen[serviceName] {
+			order = append(order, serviceName)
+		}
+	}
+	return order
+}
+
+// Service statuses. A running service with a health check is reported as
+// starting, ready or unhealthy instead of running.
+const (
+	StatusRunning   = "running"
+	StatusStopped   = "stopped"
+	StatusStarting  = "starting"
+	StatusReady     = "ready"
+	StatusUnhealthy = "unhealthy"
+	// StatusStopping marks a process that loex is in the middle of stopping.
+	StatusStopping = "stopping"
+	// StatusExited marks a supervised process that exited and was not
+	// restarted. Its entry is kept so the exit code can be reported.
+	StatusExited = "exited"
+	// StatusRestarting marks a supervised process that exited and waits
+	// for the supervisor to restart it.
+	StatusRestarting = "restarting"
+)
+
+// IsActive reports whether a status means the service process is alive.
+func IsActive(status string) bool {
+	switch status {
+	case StatusRunning, StatusStarting, StatusReady, StatusUnhealthy:
+		return true
+	}
+	return false
+}
+
+func (m *Manager) GetServiceStatus(projectName, serviceName string) (string, error) {
+	status, err := m.processStatus(projectName, serviceName)
+	if err != nil || status != StatusRunning {
+		return status, err
+	}
+
+	project, err := m.config.LoadProject(projectName)
+	if err != nil {
+		return status, nil
+	}
+	service, exists := project.Services[serviceName]
+	if !exists || service.Healthcheck == nil {
+		return status, nil
+	}
+
+	processInfo, err := m.GetProcessDetails(projectName, serviceName)
+	if err != nil {
+		return status, nil
+	}
+
+	target := m.healthTarget(project, serviceName, processInfo)
+	if health.Check(service.Healthcheck, target) == nil {
+		return StatusReady, nil
+	}
+	if time.Since(processInfo.StartTime) < health.StartupWindow(service.Healthcheck) {
+		return StatusStarting, nil
+	}
+	return StatusUnhealthy, nil
+}
+
+// processStatus reports whether the service process is running without
+// evaluating its health check.
+func (m *Manager) processStatus(projectName, serviceName string) (string, error) {
 	project, err := m.config.LoadProject(projectName)
 	if err == nil {
-		if service, exists := project.Services[serviceType]; exists {
+		if service, exists := project.Services[serviceName]; exists {
 			if strings.Contains(service.Command, "brew services start") {
 				return m.getBrewServiceStatus(service.Command)
 			}
@@ -166,65 +540,149 @@ func (m *Manager) GetServiceStatus(projectName string, serviceType models.Servic
 		return "unknown", fmt.Errorf("failed to load PIDs: %w", err)
 	}
 
-	processInfo, exists := pids.Services[serviceType]
+	processInfo, exists := pids.Services[serviceName]
 	if !exists {
-		return "stopped", nil
+		return StatusStopped, nil
 	}
 
-	if isProcessRunning(processInfo.PID) {
-		return "running", nil
+	if processInfo.Status == StatusExited {
+		return StatusExited, nil
+	}
+
+	// While the supervisor is alive it owns the entries of exited
+	// processes: it restarts them or marks them exited.
+	supervised := supervisorRunning(pids)
+	if processInfo.Status == StatusRestarting && supervised {
+		return StatusRestarting, nil
+	}
+
+	if IsServiceProcess(processInfo) {
+		return StatusRunning, nil
 	} else {
-		delete(pids.Services, serviceType)
-		m.config.SaveProjectPIDs(pids)
-		return "stopped", nil
+		if !supervised {
+			m.removePID(projectName, serviceName, processInfo.PID)
+		}
+		return StatusStopped, nil
 	}
 }
 
-func (m *Manager) IsServiceRunning(projectName string, serviceType models.ServiceType) (bool, error) {
-	status, err := m.GetServiceStatus(projectName, serviceType)
-	return status == "running", err
+func (m *Manager) IsServiceRunning(projectName, serviceName string) (bool, error) {
+	status, err := m.processStatus(projectName, serviceName)
+	return status == StatusRunning, err
 }
 
-func (m *Manager) GetAllServicesStatus(projectName string) (map[models.ServiceType]string, error) {
+func (m *Manager) GetAllServicesStatus(projectName string) (map[string]string, error) {
 	project, err := m.config.LoadProject(projectName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load project: %w", err)
 	}
 
-	status := make(map[models.ServiceType]string)
-	for serviceType := range project.Services {
-		serviceStatus, err := m.GetServiceStatus(projectName, serviceType)
+	status := make(map[string]string)
+	for serviceName := range project.Services {
+		serviceStatus, err := m.GetServiceStatus(projectName, serviceName)
 		if err != nil {
-			status[serviceType] = "error"
+			status[serviceName] = "error"
 		} else {
-			status[serviceType] = serviceStatus
+			status[serviceName] = serviceStatus
 		}
 	}
 
 	return status, nil
 }
 
-func (m *Manager) savePID(projectName string, serviceType models.ServiceType, pid int, command string) error {
-	pids, err := m.config.LoadProjectPIDs(projectName)
-	if err != nil {
-		return err
-	}
-
-	if pids.Services == nil {
-		pids.Services = make(map[models.ServiceType]models.ProcessInfo)
-	}
-
-	pids.Services[serviceType] = models.ProcessInfo{
+func (m *Manager) savePID(projectName, serviceName string, pid int, command string, logOffset int64) error {
+	info := models.ProcessInfo{
 		PID:       pid,
 		Command:   command,
 		StartTime: time.Now(),
 		Status:    "running",
+		LogOffset: logOffset,
 	}
+	recordIdentity(&info)
+
+	return m.config.UpdateProjectPIDs(projectName, func(pids *models.ProjectPIDs) error {
+		if existing, exists := pids.Services[serviceName]; exists && existing.Status != StatusExited && IsServiceProcess(existing) {
+			return fmt.Errorf("service %s was started concurrently (PID %d)", serviceName, existing.PID)
+		}
+		pids.Services[serviceName] = info
+		return nil
+	})
+}
 
-	return m.config.SaveProjectPIDs(pids)
+// removePID removes the record of a service if it still refers to pid, so
+// a process started meanwhile by another loex invocation is kept.
+func (m *Manager) removePID(projectName, serviceName string, pid int) error {
+	return m.config.UpdateProjectPIDs(projectName, func(pids *models.ProjectPIDs) error {
+		if info, exists := pids.Services[serviceName]; exists && info.PID == pid {
+			delete(pids.Services, serviceName)
+		}
+		return nil
+	})
 }
 
-func isProcessRunning(pid int) bool {
+// UpdateProcessInfo applies update to the recorded process of a service.
+// It does nothing when no process is recorded.
+func (m *Manager) UpdateProcessInfo(projectName, serviceName string, update func(info *models.ProcessInfo)) error {
+	return m.config.UpdateProjectPIDs(projectName, func(pids *models.ProjectPIDs) error {
+		info, exists := pids.Services[serviceName]
+		if !exists {
+			return nil
+		}
+		update(&info)
+		pids.Services[serviceName] = info
+		return nil
+	})
+}
+
+// SetSupervisorPID records the PID and start time of the supervisor daemon.
+// Use 0 to clear it.
+func (m *Manager) SetSupervisorPID(projectName string, pid int) error {
+	info := models.ProcessInfo{PID: pid}
+	if pid != 0 {
+		recordIdentity(&info)
+	}
+
+	return m.config.UpdateProjectPIDs(projectName, func(pids *models.ProjectPIDs) error {
+		pids.SupervisorPID = info.PID
+		pids.SupervisorStartTime = info.ProcStartTime
+		pids.SupervisorExecutable = info.Executable
+		return nil
+	})
+}
+
+// Supervisor returns the recorded supervisor daemon of a project and
+// whether it is still running. A PID taken over by another process after a
+// reboot or PID wraparound does not count as running.
+func (m *Manager) Supervisor(projectName string) (models.ProcessInfo, bool) {
+	pids, err := m.config.LoadProjectPIDs(projectName)
+	if err != nil || pids.SupervisorPID == 0 {
+		return models.ProcessInfo{}, false
+	}
+	info := supervisorInfo(pids)
+	return info, IsServiceProcess(info)
+}
+
+func supervisorInfo(pids *models.ProjectPIDs) models.ProcessInfo {
+	return models.ProcessInfo{PID: pids.SupervisorPID, ProcStartTime: pids.SupervisorStartTime, Executable: pids.SupervisorExecutable}
+}
+
+// supervisorRunning reports whether the supervisor daemon recorded in pids
+// is still running.
+func supervisorRunning(pids *models.ProjectPIDs) bool {
+	return pids.SupervisorPID != 0 && IsServiceProcess(supervisorInfo(pids))
+}
+
+// SupervisorPID returns the PID of the running supervisor daemon, or 0 if
+// none is running.
+func (m *Manager) SupervisorPID(projectName string) int {
+	if info, running := m.Supervisor(projectName); running {
+		return info.PID
+	}
+	return 0
+}
+
+// IsProcessRunning reports whether a process with the given PID exists.
+func IsProcessRunning(pid int) bool {
 	process, err := os.FindProcess(pid)
 	if err != nil {
 		return false
@@ -235,47 +693,37 @@ func isProcessRunning(pid int) bool {
 	return err == nil
 }
 
-func (m *Manager) GetProcessDetails(projectName string, serviceType models.ServiceType) (*models.ProcessInfo, error) {
+func (m *Manager) GetProcessDetails(projectName, serviceName string) (*models.ProcessInfo, error) {
 	pids, err := m.config.LoadProjectPIDs(projectName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load PIDs: %w", err)
 	}
 
-	processInfo, exists := pids.Services[serviceType]
+	processInfo, exists := pids.Services[serviceName]
 	if !exists {
-		return nil, fmt.Errorf("no process info found for service %s", serviceType)
+		return nil, fmt.Errorf("no process info found for service %s", serviceName)
 	}
 
 	return &processInfo, nil
 }
 
-func (m *Manager) GetLogs(projectName string, serviceType models.ServiceType, lines int) ([]string, error) {
-	logPath := filepath.Join(m.logger.GetLogsDir(projectName), fmt.Sprintf("%s.log", serviceType))
+func (m *Manager) GetLogs(projectName, serviceName string, lines int) ([]string, error) {
+	logPath := m.logger.GetLogPath(projectName, serviceName)
 	
-	file, err := os.Open(logPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return []string{"No logs found"}, nil
-		}
-		return nil, fmt.Errorf("failed to open log file: %w", err)
-	}
-	defer file.Close()
-
-	var logLines []string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		logLines = append(logLines, scanner.Text())
+	if _, err := os.Stat(logPath); os.IsNotExist(err) {
+		return []string{"No logs found"}, nil
 	}
 
-	if err := scanner.Err(); err != nil {
+	logLines, err := logger.ReadLines(logPath, serviceName, logger.ReadOptions{Tail: lines})
+	if err != nil {
 		return nil, fmt.Errorf("failed to read log file: %w", err)
 	}
 
-	if lines > 0 && len(logLines) > lines {
-		return logLines[len(logLines)-lines:], nil
+	result := make([]string, len(logLines))
+	for i, line := range logLines {
+		result[i] = line.Text
 	}
-
-	return logLines, nil
+	return result, nil
 }
 
 func (m *Manager) isProcessRunning(pid int) bool {
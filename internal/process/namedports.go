@@ -0,0 +1,140 @@
+package process
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+// maxPortAttempts bounds the search for a free port that is not already
+// allocated to another service.
+const maxPortAttempts = 20
+
+// NamedPorts returns the named ports of a service, with auto ports taken
+// from allocated. Auto ports that were never allocated are left out.
+func NamedPorts(service models.Service, allocated map[string]int) map[string]int {
+	ports := make(map[string]int)
+	for name, spec := range service.NamedPorts {
+		if port, ok := spec.Number(); ok {
+			ports[name] = port
+		} else if port, ok := allocated[name]; ok && spec == models.AutoPort {
+			ports[name] = port
+		}
+	}
+	return ports
+}
+
+// PortEnv returns the port variables of a service: its own named ports,
+// and the named ports of the services it depends on prefixed with their
+// name, e.g. API_PORT for the PORT of api.
+func PortEnv(project *models.Project, serviceName string, allocated map[string]map[string]int) map[string]int {
+	service := project.Services[serviceName]
+	vars := make(map[string]int)
+	for _, dep := range service.DependsOn {
+		for name, port := range NamedPorts(project.Services[dep], allocated[dep]) {
+			vars[portVarPrefix(dep)+name] = port
+		}
+	}
+	for name, port := range NamedPorts(service, allocated[serviceName]) {
+		vars[name] = port
+	}
+	return vars
+}
+
+func portVarPrefix(serviceName string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(serviceName)) + "_"
+}
+
+// AllocatedPorts returns the ports allocated to the auto named ports of a
+// project's services.
+func (m *Manager) AllocatedPorts(projectName string) map[string]map[string]int {
+	pids, err := m.config.LoadProjectPIDs(projectName)
+	if err != nil {
+		return nil
+	}
+	return pids.Ports
+}
+
+// allocatePorts allocates the auto named ports of a service that is about
+// to start and of the services it depends on, and returns all allocations
+// of the project. A port allocated before is kept while it is free; ports
+// of dependencies are kept as is since running dependencies listen on
+// them. Allocations of ports that are no longer declared are dropped.
+func (m *Manager) allocatePorts(project *models.Project, serviceName string) (map[string]map[string]int, error) {
+	var allocated map[string]map[string]int
+	err := m.config.UpdateProjectPIDs(project.Name, func(pids *models.ProjectPIDs) error {
+		for name, ports := range pids.Ports {
+			for portName := range ports {
+				if project.Services[name].NamedPorts[portName] != models.AutoPort {
+					delete(ports, portName)
+				}
+			}
+			if len(ports) == 0 {
+				delete(pids.Ports, name)
+			}
+		}
+
+		taken := make(map[int]bool)
+		for _, ports := range pids.Ports {
+			for _, port := range ports {
+				taken[port] = true
+			}
+		}
+
+		for _, name := range append([]string{serviceName}, project.Services[serviceName].DependsOn...) {
+			for _, portName := range sortedPortNames(project.Services[name].NamedPorts) {
+				if project.Services[name].NamedPorts[portName] != models.AutoPort {
+					continue
+				}
+				if port, ok := pids.Ports[name][portName]; ok && (name != serviceName || !PortListening(port)) {
+					continue
+				}
+
+				port, err := freePort(taken)
+				if err != nil {
+					return fmt.Errorf("failed to allocate %s of service %s: %w", portName, name, err)
+				}
+				taken[port] = true
+				if pids.Ports == nil {
+					pids.Ports = make(map[string]map[string]int)
+				}
+				if pids.Ports[name] == nil {
+					pids.Ports[name] = make(map[string]int)
+				}
+				pids.Ports[name][portName] = port
+			}
+		}
+
+		allocated = pids.Ports
+		return nil
+	})
+	return allocated, err
+}
+
+// freePort asks the system for a free TCP port that is not in taken.
+func freePort(taken map[int]bool) (int, error) {
+	for i := 0; i < maxPortAttempts; i++ {
+		listener, err := net.Listen("tcp", ":0")
+		if err != nil {
+			return 0, err
+		}
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+		if !taken[port] {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("no free port found")
+}
+
+func sortedPortNames(ports map[string]models.PortSpec) []string {
+	names := make([]string, 0, len(ports))
+	for name := range ports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
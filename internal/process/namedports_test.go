@@ -0,0 +1,90 @@
+package process
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/kjunh972/loex/internal/config"
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+func namedPortsProject() *models.Project {
+	return &models.Project{
+		Name: "shop",
+		Services: map[string]models.Service{
+			"api": {NamedPorts: map[string]models.PortSpec{"PORT": models.AutoPort, "ADMIN_PORT": "9001"}},
+			"web": {DependsOn: []string{"api"}, NamedPorts: map[string]models.PortSpec{"PORT": models.AutoPort}},
+		},
+	}
+}
+
+func TestPortEnv(t *testing.T) {
+	project := namedPortsProject()
+	allocated := map[string]map[string]int{"api": {"PORT": 40001}, "web": {"PORT": 40002}}
+
+	vars := PortEnv(project, "web", allocated)
+	expected := map[string]int{"PORT": 40002, "API_PORT": 40001, "API_ADMIN_PORT": 9001}
+	if len(vars) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, vars)
+	}
+	for name, port := range expected {
+		if vars[name] != port {
+			t.Errorf("Expected %s=%d, got %d", name, port, vars[name])
+		}
+	}
+}
+
+func TestAllocatePorts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManager(configManager, nil)
+	project := namedPortsProject()
+
+	first, err := manager.allocatePorts(project, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiPort, webPort := first["api"]["PORT"], first["web"]["PORT"]
+	if apiPort == 0 || webPort == 0 || apiPort == webPort {
+		t.Fatalf("Expected distinct ports for api and web, got %v", first)
+	}
+	if _, fixed := first["api"]["ADMIN_PORT"]; fixed {
+		t.Error("Expected fixed ports not to be allocated")
+	}
+
+	again, err := manager.allocatePorts(project, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again["web"]["PORT"] != webPort || again["api"]["PORT"] != apiPort {
+		t.Fatalf("Expected allocations to be stable, got %v then %v", first, again)
+	}
+
+	// A taken port is replaced for the started service, but kept for its
+	// dependency, which may be the one listening on it.
+	listenAPI, err := net.Listen("tcp", ":"+strconv.Itoa(apiPort))
+	if err != nil {
+		t.Skipf("Port %d was taken meanwhile: %v", apiPort, err)
+	}
+	defer listenAPI.Close()
+	listenWeb, err := net.Listen("tcp", ":"+strconv.Itoa(webPort))
+	if err != nil {
+		t.Skipf("Port %d was taken meanwhile: %v", webPort, err)
+	}
+	defer listenWeb.Close()
+
+	moved, err := manager.allocatePorts(project, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved["web"]["PORT"] == webPort {
+		t.Error("Expected web to get a new port while its port is taken")
+	}
+	if moved["api"]["PORT"] != apiPort {
+		t.Error("Expected the port of the dependency to be kept")
+	}
+}
@@ -0,0 +1,144 @@
+package process
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// PortOwner is a process listening on a TCP port. Project and Service are
+// set when the process belongs to a service started by loex.
+type PortOwner struct {
+	PID     int
+	Command string
+	Project string
+	Service string
+}
+
+func (o PortOwner) String() string {
+	if o.Service != "" {
+		return fmt.Sprintf("service '%s' of project '%s' (PID %d)", o.Service, o.Project, o.PID)
+	}
+	if o.Command != "" {
+		return fmt.Sprintf("PID %d (%s)", o.PID, o.Command)
+	}
+	return fmt.Sprintf("PID %d", o.PID)
+}
+
+// PortConflictError is returned by StartService when a port declared by
+// the service is already in use. Owners is empty when the listening
+// process could not be determined, e.g. because it belongs to another
+// user.
+type PortConflictError struct {
+	Service string
+	Port    int
+	Owners  []PortOwner
+}
+
+func (e *PortConflictError) Error() string {
+	if len(e.Owners) == 0 {
+		return fmt.Sprintf("port %d is already in use by another process", e.Port)
+	}
+	owners := make([]string, len(e.Owners))
+	for i, owner := range e.Owners {
+		owners[i] = owner.String()
+	}
+	return fmt.Sprintf("port %d is already in use by %s", e.Port, strings.Join(owners, ", "))
+}
+
+// LoexOwner returns the loex service holding the port, if any.
+func (e *PortConflictError) LoexOwner() *PortOwner {
+	for i := range e.Owners {
+		if e.Owners[i].Service != "" {
+			return &e.Owners[i]
+		}
+	}
+	return nil
+}
+
+// SetPortConflictHandler registers a function that is called when a port
+// of a starting service is in use. It returns true when it freed the port,
+// e.g. by stopping the loex service holding it, so the start can go on.
+func (m *Manager) SetPortConflictHandler(handler func(conflict *PortConflictError) bool) {
+	m.portConflict = handler
+}
+
+// PortListening reports whether a process listens on the TCP port. When
+// the listening sockets cannot be inspected, a connection to localhost is
+// tried instead.
+func PortListening(port int) bool {
+	if listening, err := portListening(port); err == nil {
+		return listening
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("localhost", strconv.Itoa(port)), 500*time.Millisecond)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// checkPorts makes sure the declared ports of a service are free before
+// it is started.
+func (m *Manager) checkPorts(serviceName string, ports []int) error {
+	for _, port := range ports {
+		if !PortListening(port) {
+			continue
+		}
+
+		owners, _ := portListeners(port)
+		m.identifyOwners(owners)
+		conflict := &PortConflictError{Service: serviceName, Port: port, Owners: owners}
+		if m.portConflict == nil || !m.portConflict(conflict) || !waitPortFree(port, time.Second) {
+			return conflict
+		}
+	}
+	return nil
+}
+
+// identifyOwners sets the project and service of the owners that belong
+// to a service started by loex. The listener is usually a child of the
+// recorded process, which leads the service's process group.
+func (m *Manager) identifyOwners(owners []PortOwner) {
+	if len(owners) == 0 {
+		return
+	}
+
+	projects, err := m.config.ListProjects()
+	if err != nil {
+		return
+	}
+	for _, projectName := range projects {
+		pids, err := m.config.LoadProjectPIDs(projectName)
+		if err != nil {
+			continue
+		}
+		for serviceName, info := range pids.Services {
+			if info.Status == StatusExited || !IsServiceProcess(info) {
+				continue
+			}
+			for i := range owners {
+				if owners[i].PID == info.PID {
+					owners[i].Project, owners[i].Service = projectName, serviceName
+				} else if pgid, err := syscall.Getpgid(owners[i].PID); err == nil && pgid == info.PID {
+					owners[i].Project, owners[i].Service = projectName, serviceName
+				}
+			}
+		}
+	}
+}
+
+func waitPortFree(port int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for PortListening(port) {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
+}
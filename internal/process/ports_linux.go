@@ -0,0 +1,116 @@
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// tcpListen is the state of a listening socket in /proc/net/tcp.
+const tcpListen = "0A"
+
+// portListening reports whether a socket listens on the TCP port.
+func portListening(port int) (bool, error) {
+	inodes, err := listeningInodes(port)
+	if err != nil {
+		return false, err
+	}
+	return len(inodes) > 0, nil
+}
+
+// portListeners returns the processes listening on the TCP port, found
+// through the socket links in /proc/<pid>/fd. Processes of other users
+// cannot be inspected and are left out.
+func portListeners(port int) ([]PortOwner, error) {
+	inodes, err := listeningInodes(port)
+	if err != nil || len(inodes) == 0 {
+		return nil, err
+	}
+
+	fdDirs, err := filepath.Glob("/proc/[0-9]*/fd")
+	if err != nil {
+		return nil, err
+	}
+
+	var owners []PortOwner
+	for _, fdDir := range fdDirs {
+		pid, err := strconv.Atoi(filepath.Base(filepath.Dir(fdDir)))
+		if err != nil {
+			continue
+		}
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			if inodes[strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")] {
+				owners = append(owners, PortOwner{PID: pid, Command: processCommandLine(pid)})
+				break
+			}
+		}
+	}
+	return owners, nil
+}
+
+// listeningInodes returns the inodes of the sockets listening on the TCP
+// port over IPv4 and IPv6.
+func listeningInodes(port int) (map[string]bool, error) {
+	inodes := make(map[string]bool)
+	found := false
+	for _, path := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		err = parseListeningInodes(file, port, inodes)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		found = true
+	}
+	if !found {
+		return nil, fmt.Errorf("/proc/net/tcp is not available")
+	}
+	return inodes, nil
+}
+
+// parseListeningInodes adds the inodes of the sockets of a /proc/net/tcp
+// table that listen on port.
+func parseListeningInodes(r io.Reader, port int, inodes map[string]bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != tcpListen {
+			continue
+		}
+		// The local address is HEXADDR:HEXPORT.
+		_, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		if p, err := strconv.ParseUint(hexPort, 16, 16); err == nil && int(p) == port {
+			inodes[fields[9]] = true
+		}
+	}
+	return scanner.Err()
+}
+
+// processCommandLine returns the command line of a process.
+func processCommandLine(pid int) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil || len(data) == 0 {
+		data, _ = os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	}
+	return strings.TrimSpace(string(bytes.ReplaceAll(bytes.TrimRight(data, "\x00"), []byte{0}, []byte{' '})))
+}
@@ -0,0 +1,49 @@
+package process
+
+import (
+	"net"
+	"os"
+	"strings"
+	"testing"
+)
+
+const testProcNetTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:0BB8 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 4242 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:0BB8 0100007F:D431 01 00000000:00000000 00:00000000 00000000  1000        0 4343 1 0000000000000000 20 4 30 10 -1
+   2: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 4444 1 0000000000000000 100 0 0 10 0
+`
+
+func TestParseListeningInodes(t *testing.T) {
+	inodes := make(map[string]bool)
+	if err := parseListeningInodes(strings.NewReader(testProcNetTCP), 3000, inodes); err != nil {
+		t.Fatal(err)
+	}
+	// The established connection on the same port is not a listener.
+	if len(inodes) != 1 || !inodes["4242"] {
+		t.Fatalf("Expected only inode 4242, got %v", inodes)
+	}
+}
+
+func TestPortListeners(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	if !PortListening(port) {
+		t.Fatalf("Expected port %d to be listening", port)
+	}
+	owners, err := portListeners(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owners) != 1 || owners[0].PID != os.Getpid() {
+		t.Fatalf("Expected this process to own port %d, got %+v", port, owners)
+	}
+
+	listener.Close()
+	if PortListening(port) {
+		t.Fatalf("Expected port %d to be free after closing", port)
+	}
+}
@@ -0,0 +1,59 @@
+//go:build !linux
+
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// portListening reports whether a socket listens on the TCP port.
+func portListening(port int) (bool, error) {
+	output, err := lsof(port)
+	if err != nil {
+		return false, err
+	}
+	return len(bytes.TrimSpace(output)) > 0, nil
+}
+
+// portListeners returns the processes listening on the TCP port, as
+// reported by lsof.
+func portListeners(port int) ([]PortOwner, error) {
+	output, err := lsof(port)
+	if err != nil {
+		return nil, err
+	}
+
+	// lsof -F prints one field per line: p<pid> followed by c<command>.
+	var owners []PortOwner
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "p"):
+			pid, err := strconv.Atoi(line[1:])
+			if err != nil {
+				continue
+			}
+			owners = append(owners, PortOwner{PID: pid})
+		case strings.HasPrefix(line, "c") && len(owners) > 0:
+			owners[len(owners)-1].Command = line[1:]
+		}
+	}
+	return owners, nil
+}
+
+func lsof(port int) ([]byte, error) {
+	output, err := exec.Command("lsof", "-nP", fmt.Sprintf("-iTCP:%d", port), "-sTCP:LISTEN", "-Fpc").Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(output) == 0 {
+		// lsof exits with 1 when nothing matches.
+		return nil, nil
+	}
+	return output, err
+}
@@ -0,0 +1,49 @@
+package process
+
+import (
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+// recordIdentity stores the start time and executable of a started process,
+// so a later process that reuses its PID is not mistaken for it. The
+// recorded PID is the loex shim or supervisor, so the executable is the
+// loex binary.
+func recordIdentity(info *models.ProcessInfo) {
+	if startTime, err := processStartTime(info.PID); err == nil {
+		info.ProcStartTime = startTime
+	}
+	if executable, err := processExecutable(info.PID); err == nil {
+		info.Executable = executable
+	}
+}
+
+// IsServiceProcess reports whether the recorded process is still running and
+// is the process loex started. After a reboot or PID wraparound the PID may
+// belong to another program, which has a different start time or
+// executable. Records without identity are only checked for existence; see
+// HasIdentity.
+func IsServiceProcess(info models.ProcessInfo) bool {
+	if !IsProcessRunning(info.PID) {
+		return false
+	}
+
+	// loex can always inspect the processes it started, so failing to read
+	// the identity means the PID now belongs to someone else.
+	if info.ProcStartTime != 0 {
+		if startTime, err := processStartTime(info.PID); err != nil || startTime != info.ProcStartTime {
+			return false
+		}
+	}
+	if info.Executable != "" {
+		if executable, err := processExecutable(info.PID); err != nil || executable != info.Executable {
+			return false
+		}
+	}
+	return true
+}
+
+// HasIdentity reports whether a start time was recorded for the process, so
+// IsServiceProcess checks more than that its PID exists.
+func HasIdentity(info models.ProcessInfo) bool {
+	return info.ProcStartTime != 0
+}
@@ -0,0 +1,32 @@
+package process
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// processStartTime returns the start time of a process as seconds since
+// the epoch, as reported by ps.
+func processStartTime(pid int) (uint64, error) {
+	output, err := exec.Command("ps", "-o", "lstart=", "-p", fmt.Sprint(pid)).Output()
+	if err != nil {
+		return 0, err
+	}
+
+	t, err := time.ParseInLocation("Mon Jan _2 15:04:05 2006", strings.TrimSpace(string(output)), time.Local)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(t.Unix()), nil
+}
+
+// processExecutable returns the path of the executable of a process.
+func processExecutable(pid int) (string, error) {
+	output, err := exec.Command("ps", "-o", "comm=", "-p", fmt.Sprint(pid)).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,41 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// processStartTime returns the start time of a process in clock ticks
+// since boot, field 22 of /proc/<pid>/stat.
+func processStartTime(pid int) (uint64, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+
+	// The command name in parentheses may contain spaces and parentheses,
+	// so the fields are counted from the last ')'.
+	stat := string(data)
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0, fmt.Errorf("unexpected format of /proc/%d/stat", pid)
+	}
+	fields := strings.Fields(stat[end+1:])
+	// fields[0] is field 3 (state).
+	if len(fields) < 20 {
+		return 0, fmt.Errorf("unexpected format of /proc/%d/stat", pid)
+	}
+	return strconv.ParseUint(fields[19], 10, 64)
+}
+
+// processExecutable returns the path of the executable of a process.
+func processExecutable(pid int) (string, error) {
+	path, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	if err != nil {
+		return "", err
+	}
+	// The binary may have been replaced since, e.g. by 'loex update'.
+	return strings.TrimSuffix(path, " (deleted)"), nil
+}
@@ -0,0 +1,80 @@
+package process
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kjunh972/loex/internal/config"
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+func TestIsServiceProcess(t *testing.T) {
+	cmd := exec.Command("sleep", "10")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Process.Kill()
+
+	info := models.ProcessInfo{PID: cmd.Process.Pid}
+	recordIdentity(&info)
+	if info.ProcStartTime == 0 {
+		t.Fatal("Expected a start time to be recorded")
+	}
+	if filepath.Base(info.Executable) != "sleep" {
+		t.Errorf("Expected the sleep executable, got %q", info.Executable)
+	}
+
+	if !IsServiceProcess(info) {
+		t.Error("Expected the started process to match its record")
+	}
+
+	reused := info
+	reused.ProcStartTime++
+	if IsServiceProcess(reused) {
+		t.Error("Expected a different start time to be rejected")
+	}
+
+	reused = info
+	reused.Executable = "/usr/bin/other"
+	if IsServiceProcess(reused) {
+		t.Error("Expected a different executable to be rejected")
+	}
+
+	// Records written before identities were stored only check the PID.
+	if !IsServiceProcess(models.ProcessInfo{PID: os.Getpid()}) {
+		t.Error("Expected a record without identity to match a running PID")
+	}
+}
+
+func TestStopServiceWithoutIdentity(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManager(configManager, nil)
+
+	cmd := exec.Command("sleep", "10")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Process.Kill()
+
+	err = configManager.UpdateProjectPIDs("test", func(pids *models.ProjectPIDs) error {
+		pids.Services["api"] = models.ProcessInfo{PID: cmd.Process.Pid, StartTime: time.Now(), Status: StatusRunning}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.StopService("test", "api"); err == nil {
+		t.Error("Expected a process without a recorded identity not to be stopped")
+	}
+	if !IsProcessRunning(cmd.Process.Pid) {
+		t.Error("Expected the unverified process not to be signaled")
+	}
+}
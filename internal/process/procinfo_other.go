@@ -0,0 +1,15 @@
+//go:build !linux && !darwin
+
+package process
+
+import "errors"
+
+var errUnsupported = errors.New("process identity is not supported on this platform")
+
+func processStartTime(pid int) (uint64, error) {
+	return 0, errUnsupported
+}
+
+func processExecutable(pid int) (string, error) {
+	return "", errUnsupported
+}
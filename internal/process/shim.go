@@ -0,0 +1,105 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/kjunh972/loex/internal/logger"
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+// ShimCommand is the hidden loex command that runs a service. Services are
+// started through it so that their output keeps flowing through loex, and
+// their logs can be rotated, after 'loex start' has exited.
+const ShimCommand = "_run"
+
+// shimCommand returns the command that runs argv through the shim.
+func shimCommand(projectName, serviceName, logFormat string, argv []string) (*exec.Cmd, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate loex executable: %w", err)
+	}
+
+	args := []string{ShimCommand, projectName, serviceName}
+	if logFormat != "" && logFormat != models.LogFormatRaw {
+		args = append(args, "--log-format="+logFormat)
+	}
+	args = append(append(args, "--"), argv...)
+	return exec.Command(self, args...), nil
+}
+
+// RunShim runs argv with its output appended to the service log and copied
+// to stdout, and returns the exit code of the command. Unless the log
+// format is raw, stdout and stderr are written to the log line by line in
+// that format. Stop signals are delivered to the whole process group, so
+// the shim ignores them and exits once the command has exited and its
+// output is drained.
+func RunShim(loggerManager *logger.Manager, projectName, serviceName, logFormat string, argv []string) int {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGPIPE)
+	go func() {
+		for range signals {
+		}
+	}()
+
+	logPath := loggerManager.GetLogPath(projectName, serviceName)
+	logWriter, err := logger.NewRotatingWriter(logPath, loggerManager.Policy(projectName))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer logWriter.Close()
+
+	if len(argv) == 0 {
+		fmt.Fprintf(logWriter, "empty command for service %s\n", serviceName)
+		return 1
+	}
+
+	cmd := exec.Command(argv[0], argv[1:]...)
+	if logFormat == "" || logFormat == models.LogFormatRaw {
+		output := io.MultiWriter(logWriter, ignoreErrors{os.Stdout})
+		cmd.Stdout = output
+		cmd.Stderr = output
+	} else {
+		stdout := logger.NewLineWriter(logWriter, logFormat, logger.StreamStdout)
+		stderr := logger.NewLineWriter(logWriter, logFormat, logger.StreamStderr)
+		defer stdout.Flush()
+		defer stderr.Flush()
+		cmd.Stdout = io.MultiWriter(stdout, ignoreErrors{os.Stdout})
+		cmd.Stderr = io.MultiWriter(stderr, ignoreErrors{os.Stdout})
+	}
+
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(logWriter, "failed to start service %s: %v\n", serviceName, err)
+		return 127
+	}
+
+	err = cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return 128 + int(status.Signal())
+		}
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		return 1
+	}
+	return 0
+}
+
+// ignoreErrors keeps copying output to the log when the terminal of a
+// foreground session goes away.
+type ignoreErrors struct {
+	w io.Writer
+}
+
+func (w ignoreErrors) Write(p []byte) (int, error) {
+	w.w.Write(p)
+	return len(p), nil
+}
@@ -0,0 +1,52 @@
+package process
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestParseSignal(t *testing.T) {
+	tests := map[string]syscall.Signal{
+		"":        syscall.SIGTERM,
+		"SIGINT":  syscall.SIGINT,
+		"int":     syscall.SIGINT,
+		"SIGKILL": syscall.SIGKILL,
+		"1":       syscall.SIGHUP,
+	}
+	for name, expected := range tests {
+		sig, err := ParseSignal(name)
+		if err != nil || sig != expected {
+			t.Errorf("ParseSignal(%q) = %v, %v; expected %v", name, sig, err, expected)
+		}
+	}
+
+	for _, name := range []string{"SIGFOO", "0", "-1"} {
+		if _, err := ParseSignal(name); err == nil {
+			t.Errorf("Expected an error for %q", name)
+		}
+	}
+}
+
+func TestWaitGroupExit(t *testing.T) {
+	// The shell ignores SIGTERM, like a service that does not shut down.
+	cmd := exec.Command("sh", "-c", "trap '' TERM; sleep 10 & wait")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	go cmd.Wait()
+	pgid := cmd.Process.Pid
+
+	time.Sleep(100 * time.Millisecond)
+	syscall.Kill(-pgid, syscall.SIGTERM)
+	if waitGroupExit(pgid, 300*time.Millisecond) {
+		t.Fatal("Expected the group to survive SIGTERM")
+	}
+
+	syscall.Kill(-pgid, syscall.SIGKILL)
+	if !waitGroupExit(pgid, 2*time.Second) {
+		t.Error("Expected the group to exit after SIGKILL")
+	}
+}
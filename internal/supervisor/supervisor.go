@@ -0,0 +1,344 @@
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/kjunh972/loex/internal/config"
+	"github.com/kjunh972/loex/internal/process"
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+const (
+	DefaultMaxRestarts = 5
+	DefaultWindow      = time.Minute
+	DefaultBackoff     = time.Second
+	DefaultMaxBackoff  = 30 * time.Second
+
+	pollInterval = time.Second
+)
+
+// Supervisor owns the processes of one project, waits for them to exit and
+// restarts them according to their restart policy.
+type Supervisor struct {
+	config      *config.Manager
+	process     *process.Manager
+	projectName string
+
+	mu sync.Mutex
+	// watched holds the PID currently watched for every service.
+	watched map[string]int
+	// pending counts services waiting for a delayed restart.
+	pending int
+	// history holds the restart times of every service within the
+	// policy window.
+	history map[string][]time.Time
+}
+
+func New(config *config.Manager, process *process.Manager, projectName string) *Supervisor {
+	return &Supervisor{
+		config:      config,
+		process:     process,
+		projectName: projectName,
+		watched:     make(map[string]int),
+		history:     make(map[string][]time.Time),
+	}
+}
+
+// Run starts all services of the project and supervises them until no
+// service is left running or the daemon receives SIGINT or SIGTERM.
+func (s *Supervisor) Run() error {
+	if pid := s.process.SupervisorPID(s.projectName); pid != 0 && pid != os.Getpid() {
+		return fmt.Errorf("supervisor already running for project '%s' (PID: %d)", s.projectName, pid)
+	}
+
+	project, err := s.config.LoadProject(s.projectName)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+
+	if err := s.process.SetSupervisorPID(s.projectName, os.Getpid()); err != nil {
+		return fmt.Errorf("failed to record supervisor PID: %w", err)
+	}
+	defer s.process.SetSupervisorPID(s.projectName, 0)
+
+	s.process.SetLaunchHook(func(serviceName string, cmd *exec.Cmd) {
+		s.watch(serviceName, cmd.Process.Pid)
+		go func() {
+			cmd.Wait()
+			s.exited(serviceName, cmd.Process.Pid, cmd.ProcessState.ExitCode())
+		}()
+	})
+
+	log("Supervisor started for project '%s' (PID: %d)", s.projectName, os.Getpid())
+
+	skipRunning := make(map[string]bool)
+	for _, serviceName := range project.ServiceNames() {
+		skipRunning[serviceName] = true
+	}
+	failed, err := s.process.StartServices(project, project.ServiceNames(), skipRunning)
+	if err != nil {
+		return err
+	}
+	for serviceName, err := range failed {
+		log("Service '%s' failed to start: %v", serviceName, err)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.adopt()
+		if s.idle() {
+			log("No services left to supervise, exiting")
+			return nil
+		}
+
+		select {
+		case sig := <-signals:
+			log("Received %s, supervisor exiting (services keep running)", sig)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// adopt starts watching recorded processes that were not started by this
+// supervisor, e.g. services started with 'loex start' while the daemon runs.
+func (s *Supervisor) adopt() {
+	pids, err := s.config.LoadProjectPIDs(s.projectName)
+	if err != nil {
+		return
+	}
+
+	for serviceName, info := range pids.Services {
+		if info.Status == process.StatusExited || info.Status == process.StatusStopping || info.Status == process.StatusRestarting {
+			continue
+		}
+		if !process.IsServiceProcess(info) {
+			// Either already handled by exited or about to be restarted.
+			continue
+		}
+
+		s.mu.Lock()
+		current, watching := s.watched[serviceName]
+		s.mu.Unlock()
+		if watching && current == info.PID {
+			continue
+		}
+
+		s.watch(serviceName, info.PID)
+		go s.poll(serviceName, info)
+	}
+}
+
+func (s *Supervisor) watch(serviceName string, pid int) {
+	s.mu.Lock()
+	s.watched[serviceName] = pid
+	s.mu.Unlock()
+}
+
+// poll waits for a process that is not a child of the supervisor. Its exit
+// code cannot be observed, so it is reported as -1.
+func (s *Supervisor) poll(serviceName string, info models.ProcessInfo) {
+	for process.IsServiceProcess(info) {
+		time.Sleep(pollInterval)
+	}
+	s.exited(serviceName, info.PID, -1)
+}
+
+func (s *Supervisor) idle() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.watched) == 0 && s.pending == 0
+}
+
+func (s *Supervisor) exited(serviceName string, pid, exitCode int) {
+	s.mu.Lock()
+	if s.watched[serviceName] == pid {
+		delete(s.watched, serviceName)
+	}
+	s.mu.Unlock()
+
+	if !s.stillWanted(serviceName, pid) {
+		return
+	}
+
+	log("Service '%s' (PID: %d) exited with code %d", serviceName, pid, exitCode)
+
+	project, err := s.config.LoadProject(s.projectName)
+	if err != nil {
+		s.markExited(serviceName, exitCode)
+		return
+	}
+	policy := project.Services[serviceName].Restart
+
+	if !shouldRestart(policy, exitCode) {
+		s.markExited(serviceName, exitCode)
+		return
+	}
+
+	attempt, ok := s.recordRestart(serviceName, policy)
+	if !ok {
+		log("Service '%s' restarted %d times within %s, giving up", serviceName, maxRestarts(policy), window(policy))
+		s.markExited(serviceName, exitCode)
+		return
+	}
+
+	delay := backoff(policy, attempt)
+	log("Restarting '%s' in %s", serviceName, delay)
+	s.markRestarting(serviceName, pid, time.Now().Add(delay))
+
+	s.mu.Lock()
+	s.pending++
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			s.pending--
+			s.mu.Unlock()
+		}()
+
+		time.Sleep(delay)
+
+		if !s.stillWanted(serviceName, pid) {
+			return
+		}
+
+		previous, _ := s.process.GetProcessDetails(s.projectName, serviceName)
+		if err := s.process.StartService(s.projectName, serviceName); err != nil {
+			log("Failed to restart '%s': %v", serviceName, err)
+			if current, _ := s.process.GetProcessDetails(s.projectName, serviceName); current != nil && current.PID == pid {
+				// No new process was launched, so nothing will report back.
+				s.markExited(serviceName, exitCode)
+				return
+			}
+		}
+
+		restarts := 1
+		if previous != nil {
+			restarts = previous.Restarts + 1
+		}
+		code := exitCode
+		s.process.UpdateProcessInfo(s.projectName, serviceName, func(info *models.ProcessInfo) {
+			info.Restarts = restarts
+			info.LastExitCode = &code
+		})
+	}()
+}
+
+// stillWanted reports whether the exited process is still the one recorded
+// for the service. A missing entry, a different PID or a stopping status
+// means the process was stopped on purpose.
+func (s *Supervisor) stillWanted(serviceName string, pid int) bool {
+	info, err := s.process.GetProcessDetails(s.projectName, serviceName)
+	if err != nil {
+		return false
+	}
+	return info.PID == pid && info.Status != process.StatusStopping && info.Status != process.StatusExited
+}
+
+// markRestarting records that the exited process waits for a restart at
+// next, so status checks during the backoff do not take it for stopped.
+func (s *Supervisor) markRestarting(serviceName string, pid int, next time.Time) {
+	s.process.UpdateProcessInfo(s.projectName, serviceName, func(info *models.ProcessInfo) {
+		if info.PID == pid && info.Status != process.StatusStopping && info.Status != process.StatusExited {
+			info.Status = process.StatusRestarting
+			info.NextRestart = &next
+		}
+	})
+}
+
+func (s *Supervisor) markExited(serviceName string, exitCode int) {
+	code := exitCode
+	s.process.UpdateProcessInfo(s.projectName, serviceName, func(info *models.ProcessInfo) {
+		info.Status = process.StatusExited
+		info.LastExitCode = &code
+		info.NextRestart = nil
+	})
+}
+
+// recordRestart registers a restart and returns how many restarts happened
+// within the policy window, or false when the limit is reached.
+func (s *Supervisor) recordRestart(serviceName string, policy *models.RestartPolicy) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-window(policy))
+	var recent []time.Time
+	for _, t := range s.history[serviceName] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= maxRestarts(policy) {
+		s.history[serviceName] = recent
+		return 0, false
+	}
+
+	s.history[serviceName] = append(recent, time.Now())
+	return len(recent), true
+}
+
+func shouldRestart(policy *models.RestartPolicy, exitCode int) bool {
+	if policy == nil {
+		return false
+	}
+	switch policy.Policy {
+	case models.RestartAlways:
+		return true
+	case models.RestartOnFailure:
+		return exitCode != 0
+	}
+	return false
+}
+
+func maxRestarts(policy *models.RestartPolicy) int {
+	if policy.MaxRestarts > 0 {
+		return policy.MaxRestarts
+	}
+	return DefaultMaxRestarts
+}
+
+func window(policy *models.RestartPolicy) time.Duration {
+	if policy.Window > 0 {
+		return time.Duration(policy.Window)
+	}
+	return DefaultWindow
+}
+
+// backoff returns the delay before the given restart attempt, doubling
+// from the initial backoff up to the maximum.
+func backoff(policy *models.RestartPolicy, attempt int) time.Duration {
+	delay := DefaultBackoff
+	if policy.Backoff > 0 {
+		delay = time.Duration(policy.Backoff)
+	}
+	limit := DefaultMaxBackoff
+	if policy.MaxBackoff > 0 {
+		limit = time.Duration(policy.MaxBackoff)
+	}
+
+	for i := 0; i < attempt && delay < limit; i++ {
+		delay *= 2
+	}
+	if delay > limit {
+		delay = limit
+	}
+	return delay
+}
+
+func log(format string, args ...interface{}) {
+	fmt.Printf("[%s] %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
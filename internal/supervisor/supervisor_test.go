@@ -0,0 +1,142 @@
+package supervisor
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/kjunh972/loex/internal/config"
+	"github.com/kjunh972/loex/internal/logger"
+	"github.com/kjunh972/loex/internal/process"
+	"github.com/kjunh972/loex/pkg/models"
+)
+
+func TestShouldRestart(t *testing.T) {
+	tests := []struct {
+		policy   *models.RestartPolicy
+		exitCode int
+		expected bool
+	}{
+		{nil, 1, false},
+		{&models.RestartPolicy{Policy: models.RestartNever}, 1, false},
+		{&models.RestartPolicy{Policy: models.RestartOnFailure}, 0, false},
+		{&models.RestartPolicy{Policy: models.RestartOnFailure}, 1, true},
+		{&models.RestartPolicy{Policy: models.RestartOnFailure}, -1, true},
+		{&models.RestartPolicy{Policy: models.RestartAlways}, 0, true},
+	}
+
+	for _, tt := range tests {
+		if got := shouldRestart(tt.policy, tt.exitCode); got != tt.expected {
+			t.Errorf("shouldRestart(%+v, %d) = %v, expected %v", tt.policy, tt.exitCode, got, tt.expected)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := &models.RestartPolicy{
+		Policy:     models.RestartAlways,
+		Backoff:    models.Duration(time.Second),
+		MaxBackoff: models.Duration(5 * time.Second),
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for attempt, want := range expected {
+		if got := backoff(policy, attempt); got != want {
+			t.Errorf("backoff(attempt %d) = %s, expected %s", attempt, got, want)
+		}
+	}
+}
+
+func TestRecordRestartLimit(t *testing.T) {
+	s := New(nil, nil, "test")
+	policy := &models.RestartPolicy{Policy: models.RestartAlways, MaxRestarts: 2, Window: models.Duration(time.Minute)}
+
+	for i := 0; i < 2; i++ {
+		attempt, ok := s.recordRestart("api", policy)
+		if !ok || attempt != i {
+			t.Fatalf("restart %d: got attempt %d ok %v", i, attempt, ok)
+		}
+	}
+
+	if _, ok := s.recordRestart("api", policy); ok {
+		t.Error("Expected restart limit to be reached")
+	}
+}
+
+func TestStatusDuringBackoff(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	processManager := process.NewManager(configManager, logger.NewManager(configManager))
+
+	project := &models.Project{
+		Name: "test",
+		Services: map[string]models.Service{
+			"api": {
+				Command: "false",
+				Restart: &models.RestartPolicy{Policy: models.RestartAlways, Backoff: models.Duration(2 * time.Second)},
+			},
+		},
+	}
+	if err := configManager.SaveProject(project); err != nil {
+		t.Fatal(err)
+	}
+
+	// A process that already exited, supervised by the test process.
+	exitedCmd := exec.Command("true")
+	if err := exitedCmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	pid := exitedCmd.Process.Pid
+	err = configManager.UpdateProjectPIDs("test", func(pids *models.ProjectPIDs) error {
+		pids.Services["api"] = models.ProcessInfo{PID: pid, Command: "false", StartTime: time.Now(), Status: process.StatusRunning}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := processManager.SetSupervisorPID("test", os.Getpid()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Before the supervisor notices the exit, a status check must leave
+	// the entry to it.
+	if status, _ := processManager.GetServiceStatus("test", "api"); status != process.StatusStopped {
+		t.Errorf("Expected status %s before the exit is handled, got %s", process.StatusStopped, status)
+	}
+	if _, err := processManager.GetProcessDetails("test", "api"); err != nil {
+		t.Fatalf("Expected the entry of a supervised process to be kept: %v", err)
+	}
+
+	s := New(configManager, processManager, "test")
+	s.exited("api", pid, 1)
+
+	if status, _ := processManager.GetServiceStatus("test", "api"); status != process.StatusRestarting {
+		t.Errorf("Expected status %s during the backoff, got %s", process.StatusRestarting, status)
+	}
+	info, err := processManager.GetProcessDetails("test", "api")
+	if err != nil {
+		t.Fatalf("Expected the entry to be kept during the backoff: %v", err)
+	}
+	if info.PID != pid || info.NextRestart == nil || !info.NextRestart.After(time.Now()) {
+		t.Errorf("Expected a pending restart of PID %d, got %+v", pid, info)
+	}
+
+	// Stopping the service cancels the restart.
+	if err := processManager.StopService("test", "api"); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !s.idle() {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the pending restart to finish")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if _, err := processManager.GetProcessDetails("test", "api"); err == nil {
+		t.Error("Expected no process to be started after the service was stopped")
+	}
+}
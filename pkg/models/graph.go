@@ -0,0 +1,222 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// kindRank keeps the historical db -> backend -> frontend start order for
+// projects that do not declare any depends_on. Unlabeled services start
+// between backends and frontends.
+var kindRank = map[ServiceType]int{
+	ServiceDB:       0,
+	ServiceBackend:  1,
+	ServiceFrontend: 3,
+}
+
+const unlabeledRank = 2
+
+// HasDependencies reports whether any service declares depends_on.
+func (p *Project) HasDependencies() bool {
+	for _, service := range p.Services {
+		if len(service.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// WithDependencies returns the given services together with all of their
+// transitive dependencies.
+func (p *Project) WithDependencies(names []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var visit func(name, from string) error
+	visit = func(name, from string) error {
+		if seen[name] {
+			return nil
+		}
+		service, exists := p.Services[name]
+		if !exists {
+			if from != "" {
+				return fmt.Errorf("service '%s' depends on unknown service '%s'", from, name)
+			}
+			return fmt.Errorf("service '%s' not configured for project '%s'", name, p.Name)
+		}
+		seen[name] = true
+		for _, dep := range service.DependsOn {
+			if err := visit(dep, name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]string, 0, len(seen))
+	for name := range seen {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// StartLevels groups the given services into levels that can be started in
+// order. Every service in a level only depends on services in earlier
+// levels, so the services within one level can be started in parallel.
+// Dependencies outside of names are assumed to be satisfied already.
+func (p *Project) StartLevels(names []string) ([][]string, error) {
+	if !p.HasDependencies() {
+		return p.kindLevels(names), nil
+	}
+
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		if _, exists := p.Services[name]; !exists {
+			return nil, fmt.Errorf("service '%s' not configured for project '%s'", name, p.Name)
+		}
+		selected[name] = true
+	}
+
+	pending := make(map[string]int, len(selected))
+	dependents := make(map[string][]string)
+	for name := range selected {
+		for _, dep := range p.Services[name].DependsOn {
+			if _, exists := p.Services[dep]; !exists {
+				return nil, fmt.Errorf("service '%s' depends on unknown service '%s'", name, dep)
+			}
+			if selected[dep] {
+				pending[name]++
+				dependents[dep] = append(dependents[dep], name)
+			}
+		}
+	}
+
+	var levels [][]string
+	var current []string
+	for name := range selected {
+		if pending[name] == 0 {
+			current = append(current, name)
+		}
+	}
+
+	placed := 0
+	for len(current) > 0 {
+		sort.Strings(current)
+		levels = append(levels, current)
+		placed += len(current)
+
+		var next []string
+		for _, name := range current {
+			for _, dependent := range dependents[name] {
+				pending[dependent]--
+				if pending[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+		current = next
+	}
+
+	if placed < len(selected) {
+		return nil, p.findCycle(selected)
+	}
+
+	return levels, nil
+}
+
+// StopLevels returns the start levels in reverse, so dependents are stopped
+// before the services they depend on.
+func (p *Project) StopLevels(names []string) ([][]string, error) {
+	levels, err := p.StartLevels(names)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(levels)-1; i < j; i, j = i+1, j-1 {
+		levels[i], levels[j] = levels[j], levels[i]
+	}
+	return levels, nil
+}
+
+// ValidateDependencies checks that every depends_on entry refers to a
+// configured service and that the graph has no cycles.
+func (p *Project) ValidateDependencies() error {
+	_, err := p.StartLevels(p.ServiceNames())
+	return err
+}
+
+func (p *Project) kindLevels(names []string) [][]string {
+	byRank := make(map[int][]string)
+	for _, name := range names {
+		rank, known := kindRank[p.Services[name].Kind]
+		if !known {
+			rank = unlabeledRank
+		}
+		byRank[rank] = append(byRank[rank], name)
+	}
+
+	var levels [][]string
+	for rank := 0; rank <= 3; rank++ {
+		if level := byRank[rank]; len(level) > 0 {
+			sort.Strings(level)
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+func (p *Project) findCycle(selected map[string]bool) error {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var stack []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, dep := range p.Services[name].DependsOn {
+			if !selected[dep] {
+				continue
+			}
+			switch state[dep] {
+			case visiting:
+				for i, n := range stack {
+					if n == dep {
+						return append(append([]string{}, stack[i:]...), dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = done
+		return nil
+	}
+
+	names := make([]string, 0, len(selected))
+	for name := range selected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if state[name] == unvisited {
+			if cycle := visit(name); cycle != nil {
+				return fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+			}
+		}
+	}
+	return fmt.Errorf("dependency cycle detected")
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newGraphProject(deps map[string][]string) *Project {
+	project := &Project{Name: "test", Services: make(map[string]Service)}
+	for name, dependsOn := range deps {
+		project.Services[name] = Service{Command: "true", DependsOn: dependsOn}
+	}
+	return project
+}
+
+func TestStartLevels(t *testing.T) {
+	project := newGraphProject(map[string][]string{
+		"web":       {"api"},
+		"api":       {"db", "redis"},
+		"worker":    {"redis"},
+		"scheduler": {"api", "worker"},
+		"db":        nil,
+		"redis":     nil,
+	})
+
+	levels, err := project.StartLevels(project.ServiceNames())
+	if err != nil {
+		t.Fatalf("StartLevels failed: %v", err)
+	}
+
+	expected := [][]string{
+		{"db", "redis"},
+		{"api", "worker"},
+		{"scheduler", "web"},
+	}
+	if !reflect.DeepEqual(levels, expected) {
+		t.Errorf("Expected levels %v, got %v", expected, levels)
+	}
+
+	stopLevels, err := project.StopLevels(project.ServiceNames())
+	if err != nil {
+		t.Fatalf("StopLevels failed: %v", err)
+	}
+	if !reflect.DeepEqual(stopLevels[0], expected[2]) || !reflect.DeepEqual(stopLevels[2], expected[0]) {
+		t.Errorf("Expected reversed levels, got %v", stopLevels)
+	}
+}
+
+func TestWithDependencies(t *testing.T) {
+	project := newGraphProject(map[string][]string{
+		"web":   {"api"},
+		"api":   {"db"},
+		"db":    nil,
+		"other": nil,
+	})
+
+	names, err := project.WithDependencies([]string{"web"})
+	if err != nil {
+		t.Fatalf("WithDependencies failed: %v", err)
+	}
+
+	expected := []string{"api", "db", "web"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
+
+func TestStartLevelsDetectsCycle(t *testing.T) {
+	project := newGraphProject(map[string][]string{
+		"a": {"b"},
+		"b": {"c"},
+		"c": {"a"},
+		"d": nil,
+	})
+
+	_, err := project.StartLevels(project.ServiceNames())
+	if err == nil {
+		t.Fatal("Expected cycle error, got nil")
+	}
+	if !strings.Contains(err.Error(), "a -> b -> c -> a") {
+		t.Errorf("Expected cycle path in error, got: %v", err)
+	}
+}
+
+func TestStartLevelsUnknownDependency(t *testing.T) {
+	project := newGraphProject(map[string][]string{
+		"api": {"db"},
+	})
+
+	if err := project.ValidateDependencies(); err == nil {
+		t.Error("Expected error for unknown dependency, got nil")
+	}
+}
+
+func TestStartLevelsLegacyKindOrder(t *testing.T) {
+	project := &Project{Name: "legacy", Services: map[string]Service{
+		"frontend": {Kind: ServiceFrontend},
+		"backend":  {Kind: ServiceBackend},
+		"db":       {Kind: ServiceDB},
+		"worker":   {},
+	}}
+
+	levels, err := project.StartLevels(project.ServiceNames())
+	if err != nil {
+		t.Fatalf("StartLevels failed: %v", err)
+	}
+
+	expected := [][]string{{"db"}, {"backend"}, {"worker"}, {"frontend"}}
+	if !reflect.DeepEqual(levels, expected) {
+		t.Errorf("Expected levels %v, got %v", expected, levels)
+	}
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// AutoPort lets loex allocate a free port for a named port.
+const AutoPort PortSpec = "auto"
+
+// PortSpec is the value of a named port: a port number, or "auto" for a
+// port allocated by loex. Numbers are stored as JSON numbers.
+type PortSpec string
+
+// Number returns the port of a fixed named port.
+func (p PortSpec) Number() (int, bool) {
+	port, err := strconv.Atoi(string(p))
+	if err != nil || port < 1 || port > 65535 {
+		return 0, false
+	}
+	return port, true
+}
+
+// Validate reports whether the spec is "auto" or a valid port number.
+func (p PortSpec) Validate() error {
+	if _, ok := p.Number(); ok || p == AutoPort {
+		return nil
+	}
+	return fmt.Errorf("invalid port '%s', use a port number or auto", string(p))
+}
+
+func (p PortSpec) MarshalJSON() ([]byte, error) {
+	if port, ok := p.Number(); ok {
+		return json.Marshal(port)
+	}
+	return json.Marshal(string(p))
+}
+
+func (p *PortSpec) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case float64:
+		*p = PortSpec(strconv.Itoa(int(v)))
+	case string:
+		*p = PortSpec(v)
+	default:
+		return fmt.Errorf("invalid port: %s", string(data))
+	}
+	return p.Validate()
+}
@@ -1,7 +1,14 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
 
+// ServiceType is an optional label describing what kind of service a
+// named service is. Any value is accepted; the constants below are the
+// kinds loex knows how to detect.
 type ServiceType string
 
 const (
@@ -11,18 +18,154 @@ const (
 )
 
 type Service struct {
-	Type    ServiceType `json:"type"`
+	Kind    ServiceType `json:"kind,omitempty"`
 	Command string      `json:"command"`
-	Dir     string      `json:"dir"`
-	PID     int         `json:"pid,omitempty"`
-	Status  string      `json:"status,omitempty"`
+	// Args is the explicit argv form of the command. When set it is run
+	// as is and Command is only used for display.
+	Args []string `json:"args,omitempty"`
+	// Shell, when set, runs Command with this shell and -c.
+	Shell     string   `json:"shell,omitempty"`
+	Dir       string   `json:"dir"`
+	DependsOn []string `json:"depends_on,omitempty"`
+	// Ports lists the host ports the service listens on.
+	Ports []int `json:"ports,omitempty"`
+	// NamedPorts maps environment variable names to ports. The service
+	// gets each port in its variable and services depending on it get it
+	// as <SERVICE>_<NAME>. Auto ports are allocated when the service
+	// starts.
+	NamedPorts map[string]PortSpec `json:"named_ports,omitempty"`
+
+	// Env and EnvFiles add to the project environment. Env files are
+	// dotenv files relative to Dir; Env takes precedence over them.
+	Env      map[string]string `json:"env,omitempty"`
+	EnvFiles []string          `json:"env_files,omitempty"`
+
+	// Healthcheck, when set, is used to decide when the service is ready.
+	Healthcheck *Healthcheck `json:"healthcheck,omitempty"`
+
+	// Restart controls what the supervisor daemon does when the service
+	// exits. Services without a policy are never restarted.
+	Restart *RestartPolicy `json:"restart,omitempty"`
+
+	// StopSignal is sent to stop the service (default SIGTERM). If the
+	// service is still running after StopTimeout (default 10s) it is
+	// killed.
+	StopSignal  string   `json:"stop_signal,omitempty"`
+	StopTimeout Duration `json:"stop_timeout,omitempty"`
+
+	// LogFormat selects how output is written to the service log. The
+	// default is raw output.
+	LogFormat string `json:"log_format,omitempty"`
+
+	PID    int    `json:"pid,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// Healthcheck describes a readiness probe. Exactly one of TCP, HTTP,
+// Command or LogPattern is expected to be set.
+type Healthcheck struct {
+	// TCP is a port or host:port that must accept connections.
+	TCP string `json:"tcp,omitempty"`
+	// HTTP is a URL that must answer a GET with HTTPStatus (default 200).
+	HTTP       string `json:"http,omitempty"`
+	HTTPStatus int    `json:"http_status,omitempty"`
+	// Command is run with /bin/sh -c in the service directory and must
+	// exit 0.
+	Command string `json:"command,omitempty"`
+	// LogPattern is a regular expression that must appear in the service
+	// log after the service was started.
+	LogPattern string `json:"log_pattern,omitempty"`
+
+	Timeout  Duration `json:"timeout,omitempty"`
+	Interval Duration `json:"interval,omitempty"`
+	Retries  int      `json:"retries,omitempty"`
+}
+
+// UnmarshalJSON accepts the legacy "type" field written by older versions
+// and maps it onto Kind.
+func (s *Service) UnmarshalJSON(data []byte) error {
+	type service Service
+	aux := struct {
+		*service
+		Type ServiceType `json:"type"`
+	}{service: (*service)(s)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if s.Kind == "" {
+		s.Kind = aux.Type
+	}
+	return nil
+}
+
+// Log formats. Timestamped prefixes every line with its time and stream;
+// JSON writes one object with ts, stream and msg per line.
+const (
+	LogFormatRaw         = "raw"
+	LogFormatTimestamped = "timestamped"
+	LogFormatJSON        = "json"
+)
+
+// Restart policies understood by the supervisor daemon.
+const (
+	RestartNever     = "never"
+	RestartOnFailure = "on-failure"
+	RestartAlways    = "always"
+)
+
+// RestartPolicy describes when and how often a supervised service is
+// restarted. Restarts are delayed by an exponential backoff starting at
+// Backoff and capped at MaxBackoff. The supervisor gives up once
+// MaxRestarts restarts happened within Window.
+type RestartPolicy struct {
+	Policy      string   `json:"policy"`
+	MaxRestarts int      `json:"max_restarts,omitempty"`
+	Window      Duration `json:"window,omitempty"`
+	Backoff     Duration `json:"backoff,omitempty"`
+	MaxBackoff  Duration `json:"max_backoff,omitempty"`
+}
+
+// LogPolicy controls rotation and retention of service logs. A log is
+// rotated once it grows beyond MaxSizeMB or its oldest line is older than
+// MaxAge. Only the newest MaxFiles rotated files are kept, and rotated
+// files older than MaxAge are removed. Unset fields fall back to the
+// global policy and then to the defaults.
+type LogPolicy struct {
+	MaxSizeMB int      `json:"max_size_mb,omitempty"`
+	MaxFiles  int      `json:"max_files,omitempty"`
+	MaxAge    Duration `json:"max_age,omitempty"`
+	Compress  *bool    `json:"compress,omitempty"`
 }
 
 type Project struct {
 	Name     string             `json:"name"`
-	Services map[ServiceType]Service `json:"services"`
-	Created  time.Time          `json:"created"`
-	Updated  time.Time          `json:"updated"`
+	Services map[string]Service `json:"services"`
+	// Env and EnvFiles apply to all services of the project.
+	Env      map[string]string `json:"env,omitempty"`
+	EnvFiles []string          `json:"env_files,omitempty"`
+	// Manifest is the path of the loex.yaml the project is defined in, if
+	// any. Such projects are read-only in the registry.
+	Manifest string `json:"manifest,omitempty"`
+	// Logs overrides the global log policy for this project.
+	Logs    *LogPolicy `json:"logs,omitempty"`
+	Created time.Time  `json:"created"`
+	Updated time.Time  `json:"updated"`
+}
+
+// Settings holds global loex settings.
+type Settings struct {
+	Logs *LogPolicy `json:"logs,omitempty"`
+}
+
+// ServiceNames returns the configured service names in a stable order.
+func (p *Project) ServiceNames() []string {
+	names := make([]string, 0, len(p.Services))
+	for name := range p.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 type ProcessInfo struct {
@@ -30,10 +173,36 @@ type ProcessInfo struct {
 	Command   string    `json:"command"`
 	StartTime time.Time `json:"start_time"`
 	Status    string    `json:"status"`
+	// LogOffset is the size of the service log when the process was
+	// started, so log based health checks only look at new output.
+	LogOffset int64 `json:"log_offset,omitempty"`
+
+	// ProcStartTime and Executable identify the process, so a PID reused by
+	// another program is not taken for the service. ProcStartTime is in
+	// clock ticks since boot on Linux and seconds since the epoch on macOS;
+	// Executable is the path of the loex binary running the service.
+	ProcStartTime uint64 `json:"proc_start_time,omitempty"`
+	Executable    string `json:"executable,omitempty"`
+
+	// Restarts and LastExitCode are maintained by the supervisor daemon.
+	Restarts     int  `json:"restarts,omitempty"`
+	LastExitCode *int `json:"last_exit_code,omitempty"`
+	// NextRestart is when the supervisor restarts an exited process that is
+	// waiting out its restart backoff.
+	NextRestart *time.Time `json:"next_restart,omitempty"`
 }
 
 type ProjectPIDs struct {
 	ProjectName string                 `json:"project_name"`
-	Services    map[ServiceType]ProcessInfo `json:"services"`
-	Updated     time.Time              `json:"updated"`
-}
\ No newline at end of file
+	Services    map[string]ProcessInfo `json:"services"`
+	// SupervisorPID is the PID of the running supervisor daemon, if any, and
+	// SupervisorStartTime and SupervisorExecutable its identity, as in
+	// ProcessInfo.
+	SupervisorPID        int    `json:"supervisor_pid,omitempty"`
+	SupervisorStartTime  uint64 `json:"supervisor_start_time,omitempty"`
+	SupervisorExecutable string `json:"supervisor_executable,omitempty"`
+	// Ports holds the ports allocated to the auto named ports of each
+	// service, so they stay the same across restarts while they are free.
+	Ports   map[string]map[string]int `json:"ports,omitempty"`
+	Updated time.Time                 `json:"updated"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestLegacyProjectLoads(t *testing.T) {
+	data := []byte(`{
+		"name": "legacy",
+		"services": {
+			"frontend": {"type": "frontend", "command": "npm start", "dir": "/app/web"},
+			"db": {"type": "db", "command": "brew services start mysql", "dir": "/app"}
+		}
+	}`)
+
+	var project Project
+	if err := json.Unmarshal(data, &project); err != nil {
+		t.Fatalf("Failed to unmarshal legacy project: %v", err)
+	}
+
+	if got := project.Services["frontend"].Kind; got != ServiceFrontend {
+		t.Errorf("Expected frontend kind %s, got %s", ServiceFrontend, got)
+	}
+	if got := project.Services["db"].Kind; got != ServiceDB {
+		t.Errorf("Expected db kind %s, got %s", ServiceDB, got)
+	}
+
+	expected := []string{"db", "frontend"}
+	if names := project.ServiceNames(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected service names %v, got %v", expected, names)
+	}
+}
+
+func TestNamedPortsJSON(t *testing.T) {
+	var service Service
+	if err := json.Unmarshal([]byte(`{"command": "go run .", "named_ports": {"PORT": "auto", "ADMIN_PORT": 9001}}`), &service); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]PortSpec{"PORT": AutoPort, "ADMIN_PORT": "9001"}
+	if !reflect.DeepEqual(service.NamedPorts, expected) {
+		t.Fatalf("Expected %v, got %v", expected, service.NamedPorts)
+	}
+
+	data, err := json.Marshal(service.NamedPorts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"ADMIN_PORT":9001,"PORT":"auto"}` {
+		t.Errorf("Unexpected encoding: %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"named_ports": {"PORT": "80000"}}`), &service); err == nil {
+		t.Error("Expected an error for an invalid port")
+	}
+}